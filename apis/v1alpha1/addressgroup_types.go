@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AddressGroupSpec defines the desired state of a Flow address group.
+type AddressGroupSpec struct {
+	// Name of the address group in Prism.
+	Name string `json:"name"`
+
+	// Description of the address group.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// CIDRs are the IP addresses and prefixes of the group, e.g. 10.0.0.0/24
+	// or 192.168.1.10/32.
+	// +kubebuilder:validation:MinItems=1
+	CIDRs []string `json:"cidrs"`
+
+	// Datacenter selects the Prism Central managing the address group from
+	// the ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in
+	// the ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// AddressGroupStatus defines the observed state of a Flow address group.
+type AddressGroupStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the address group.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+}
+
+// An AddressGroup is a named set of IP prefixes used in SecurityPolicy rules.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type AddressGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AddressGroupSpec   `json:"spec"`
+	Status AddressGroupStatus `json:"status,omitempty"`
+}
+
+func (in *AddressGroup) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this AddressGroup.
+func (in *AddressGroup) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this AddressGroup.
+func (in *AddressGroup) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this AddressGroup.
+func (in *AddressGroup) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// AddressGroupList contains a list of AddressGroup.
+type AddressGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AddressGroup `json:"items"`
+}
+
+func (in *AddressGroupList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&AddressGroup{}, &AddressGroupList{})
+}
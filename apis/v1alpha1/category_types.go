@@ -0,0 +1,171 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// CategorySpec defines the desired state of a Prism category key.
+type CategorySpec struct {
+	// Name of the category key in Prism.
+	Name string `json:"name"`
+
+	// Description of the category key.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Cardinality is the maximum number of values of this category that can
+	// be attached to a single entity.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Cardinality int `json:"cardinality,omitempty"`
+
+	// Datacenter selects the Prism Central managing the category from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// CategoryStatus defines the observed state of a Prism category key.
+type CategoryStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// SystemDefined is true for categories built into Prism, which are never
+	// deleted.
+	// +optional
+	SystemDefined bool `json:"systemDefined,omitempty"`
+}
+
+// A Category is a Prism category key, e.g. Environment or CostCenter.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type Category struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CategorySpec   `json:"spec"`
+	Status CategoryStatus `json:"status,omitempty"`
+}
+
+func (in *Category) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this Category.
+func (in *Category) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this Category.
+func (in *Category) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this Category.
+func (in *Category) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// CategoryList contains a list of Category.
+type CategoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Category `json:"items"`
+}
+
+func (in *CategoryList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// CategoryValueSpec defines the desired state of a value of a Prism category.
+type CategoryValueSpec struct {
+	// Category is the name of the category key the value belongs to.
+	Category string `json:"category"`
+
+	// Value in Prism.
+	Value string `json:"value"`
+
+	// Description of the value.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Datacenter selects the Prism Central managing the value from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// CategoryValueStatus defines the observed state of a value of a Prism category.
+type CategoryValueStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// SystemDefined is true for values built into Prism, which are never
+	// deleted.
+	// +optional
+	SystemDefined bool `json:"systemDefined,omitempty"`
+}
+
+// A CategoryValue is a value of a Prism category key, e.g. Environment=Production.
+// It is deleted from Prism only once no entity uses it.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="CATEGORY",type="string",JSONPath=".spec.category"
+// +kubebuilder:printcolumn:name="VALUE",type="string",JSONPath=".spec.value"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type CategoryValue struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CategoryValueSpec   `json:"spec"`
+	Status CategoryValueStatus `json:"status,omitempty"`
+}
+
+func (in *CategoryValue) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this CategoryValue.
+func (in *CategoryValue) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this CategoryValue.
+func (in *CategoryValue) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this CategoryValue.
+func (in *CategoryValue) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// CategoryValueList contains a list of CategoryValue.
+type CategoryValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CategoryValue `json:"items"`
+}
+
+func (in *CategoryValueList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&Category{}, &CategoryList{}, &CategoryValue{}, &CategoryValueList{})
+}
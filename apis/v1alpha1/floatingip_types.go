@@ -0,0 +1,98 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FloatingIPSpec defines the desired state of a floating IP.
+type FloatingIPSpec struct {
+	// ExternalSubnetName is the external subnet the address is allocated
+	// from. It cannot be changed after creation.
+	ExternalSubnetName string `json:"externalSubnetName"`
+
+	// VPCRef references the VPC of PrivateIP.
+	// +optional
+	VPCRef *xpv1.Reference `json:"vpcRef,omitempty"`
+
+	// PrivateIP is the VPC address the floating IP is translated to. The
+	// floating IP is allocated but not associated when unset.
+	// +optional
+	PrivateIP string `json:"privateIp,omitempty"`
+
+	// Datacenter selects the Prism Central managing the floating IP from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// FloatingIPStatus defines the observed state of a floating IP.
+type FloatingIPStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the floating IP.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// Address is the allocated floating IP address.
+	// +optional
+	Address string `json:"address,omitempty"`
+}
+
+// A FloatingIP is an address of an external subnet translated to an address
+// in a VPC. VirtualMachines request their own floating IP through
+// spec.floatingIp.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="ADDRESS",type="string",JSONPath=".status.address"
+// +kubebuilder:printcolumn:name="PRIVATE-IP",type="string",JSONPath=".spec.privateIp"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type FloatingIP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FloatingIPSpec   `json:"spec"`
+	Status FloatingIPStatus `json:"status,omitempty"`
+}
+
+func (in *FloatingIP) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this FloatingIP.
+func (in *FloatingIP) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this FloatingIP.
+func (in *FloatingIP) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this FloatingIP.
+func (in *FloatingIP) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// FloatingIPList contains a list of FloatingIP.
+type FloatingIPList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FloatingIP `json:"items"`
+}
+
+func (in *FloatingIPList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&FloatingIP{}, &FloatingIPList{})
+}
@@ -0,0 +1,112 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// HostAffinityPolicySpec defines the desired state of a Prism VM-host
+// affinity policy.
+type HostAffinityPolicySpec struct {
+	// Name of the affinity policy in Prism.
+	Name string `json:"name"`
+
+	// Description of the affinity policy.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// VMCategories selects the VMs the policy applies to. A VM matching any
+	// of the categories is selected.
+	// +kubebuilder:validation:MinProperties=1
+	VMCategories map[string]string `json:"vmCategories"`
+
+	// HostCategories selects the hosts the selected VMs may run on. A host
+	// matching any of the categories is selected.
+	// +kubebuilder:validation:MinProperties=1
+	HostCategories map[string]string `json:"hostCategories"`
+
+	// Datacenter selects the Prism Central managing the policy from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// HostAffinityPolicyStatus defines the observed state of a Prism VM-host
+// affinity policy.
+type HostAffinityPolicyStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the affinity policy.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// NumVMs is the number of VMs the policy applies to.
+	// +optional
+	NumVMs int `json:"numVms,omitempty"`
+
+	// NumHosts is the number of hosts the selected VMs may run on.
+	// +optional
+	NumHosts int `json:"numHosts,omitempty"`
+
+	// NumCompliantVMs is the number of selected VMs running on one of the
+	// selected hosts.
+	// +optional
+	NumCompliantVMs int `json:"numCompliantVms,omitempty"`
+}
+
+// A HostAffinityPolicy is a Prism VM-host affinity policy, restricting the
+// VMs in some categories to the hosts in others.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="VMS",type="integer",JSONPath=".status.numVms"
+// +kubebuilder:printcolumn:name="HOSTS",type="integer",JSONPath=".status.numHosts"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type HostAffinityPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HostAffinityPolicySpec   `json:"spec"`
+	Status HostAffinityPolicyStatus `json:"status,omitempty"`
+}
+
+func (in *HostAffinityPolicy) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this HostAffinityPolicy.
+func (in *HostAffinityPolicy) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this HostAffinityPolicy.
+func (in *HostAffinityPolicy) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this HostAffinityPolicy.
+func (in *HostAffinityPolicy) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// HostAffinityPolicyList contains a list of HostAffinityPolicy.
+type HostAffinityPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HostAffinityPolicy `json:"items"`
+}
+
+func (in *HostAffinityPolicyList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&HostAffinityPolicy{}, &HostAffinityPolicyList{})
+}
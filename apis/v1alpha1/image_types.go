@@ -0,0 +1,162 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Image types.
+const (
+	ImageTypeDisk = "DISK_IMAGE"
+	ImageTypeISO  = "ISO_IMAGE"
+)
+
+// ImageSpec defines the desired state of a Prism image.
+type ImageSpec struct {
+	// Name of the image in Prism. VirtualMachine imageName lookups match it.
+	Name string `json:"name"`
+
+	// Description of the image.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ImageType is DISK_IMAGE or ISO_IMAGE.
+	// +kubebuilder:validation:Enum=DISK_IMAGE;ISO_IMAGE
+	// +kubebuilder:default=DISK_IMAGE
+	// +optional
+	ImageType string `json:"imageType,omitempty"`
+
+	// Source the image is created from. Exactly one of its fields must be set.
+	// It cannot be changed after creation.
+	Source ImageSource `json:"source"`
+
+	// Checksum the downloaded image is verified against.
+	// +optional
+	Checksum *ImageChecksum `json:"checksum,omitempty"`
+
+	// ClusterNames lists the clusters the image is placed on. Defaults to all
+	// clusters registered with the Prism Central.
+	// +optional
+	ClusterNames []string `json:"clusterNames,omitempty"`
+
+	// Categories are Prism category key/value pairs attached to the image.
+	// +optional
+	Categories map[string]string `json:"categories,omitempty"`
+
+	// Datacenter selects the Prism Central managing the image from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// ImageSource defines where an image's content comes from.
+type ImageSource struct {
+	// URL the image is downloaded from.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// VMDisk copies a disk of an existing VM.
+	// +optional
+	VMDisk *ImageVMDiskSource `json:"vmDisk,omitempty"`
+
+	// ImageUUID copies an existing image.
+	// +optional
+	ImageUUID string `json:"imageUuid,omitempty"`
+
+	// ImageName copies the existing image with this exact name.
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+}
+
+// ImageVMDiskSource identifies a disk of an existing VM.
+type ImageVMDiskSource struct {
+	// VMUUID is the UUID of the VM.
+	VMUUID string `json:"vmUuid"`
+
+	// DeviceIndex of the disk. Defaults to the boot disk.
+	// +kubebuilder:default=0
+	// +optional
+	DeviceIndex int `json:"deviceIndex,omitempty"`
+}
+
+// ImageChecksum is the expected checksum of an image.
+type ImageChecksum struct {
+	// Algorithm is SHA_1 or SHA_256.
+	// +kubebuilder:validation:Enum=SHA_1;SHA_256
+	Algorithm string `json:"algorithm"`
+
+	// Value is the hex encoded checksum.
+	Value string `json:"value"`
+}
+
+// ImageStatus defines the observed state of a Prism image.
+type ImageStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the image.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// State of the image in Prism, e.g. PENDING, COMPLETE or ERROR.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// SizeBytes is the size of the image.
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+}
+
+// An Image is a Prism disk or ISO image, e.g. a golden OS image.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type Image struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImageSpec   `json:"spec"`
+	Status ImageStatus `json:"status,omitempty"`
+}
+
+func (in *Image) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this Image.
+func (in *Image) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this Image.
+func (in *Image) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this Image.
+func (in *Image) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// ImageList contains a list of Image.
+type ImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Image `json:"items"`
+}
+
+func (in *ImageList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&Image{}, &ImageList{})
+}
@@ -0,0 +1,135 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ProjectSpec defines the desired state of a Prism project.
+type ProjectSpec struct {
+	// Name of the project in Prism.
+	Name string `json:"name"`
+
+	// Description of the project.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Quota limits the resources consumed by the project's VMs.
+	// +optional
+	Quota *ProjectQuota `json:"quota,omitempty"`
+
+	// ClusterNames lists the clusters the project's VMs may be placed on.
+	// +optional
+	ClusterNames []string `json:"clusterNames,omitempty"`
+
+	// SubnetNames lists the subnets the project's VMs may use.
+	// +optional
+	SubnetNames []string `json:"subnetNames,omitempty"`
+
+	// DefaultSubnetName is the subnet used when a VM does not specify one.
+	// It must be listed in SubnetNames.
+	// +optional
+	DefaultSubnetName string `json:"defaultSubnetName,omitempty"`
+
+	// ImageNames lists the images the project's VMs may be created from.
+	// +optional
+	ImageNames []string `json:"imageNames,omitempty"`
+
+	// MemberGroups are the directory groups granted a role in the project.
+	// +optional
+	MemberGroups []ProjectMemberGroup `json:"memberGroups,omitempty"`
+
+	// Datacenter selects the Prism Central managing the project from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// ProjectQuota defines the resource limits of a project. Unset limits are
+// unlimited.
+type ProjectQuota struct {
+	// VCPUs is the total number of vCPUs.
+	// +optional
+	VCPUs int `json:"vcpus,omitempty"`
+
+	// MemoryMiB is the total memory in MiB.
+	// +optional
+	MemoryMiB int `json:"memoryMib,omitempty"`
+
+	// StorageGiB is the total disk capacity in GiB.
+	// +optional
+	StorageGiB int `json:"storageGib,omitempty"`
+}
+
+// ProjectMemberGroup grants a directory group a role in a project.
+type ProjectMemberGroup struct {
+	// Group is the distinguished name of the directory group.
+	Group string `json:"group"`
+
+	// Role is the name of the Prism role granted, e.g. Developer or Project Admin.
+	Role string `json:"role"`
+}
+
+// ProjectStatus defines the observed state of a Prism project.
+type ProjectStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the project.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+}
+
+// A Project is a Prism project, which enforces quotas and RBAC for the VMs
+// placed in it.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type Project struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectSpec   `json:"spec"`
+	Status ProjectStatus `json:"status,omitempty"`
+}
+
+func (in *Project) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this Project.
+func (in *Project) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this Project.
+func (in *Project) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this Project.
+func (in *Project) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectList contains a list of Project.
+type ProjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Project `json:"items"`
+}
+
+func (in *ProjectList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&Project{}, &ProjectList{})
+}
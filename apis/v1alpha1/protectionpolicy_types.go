@@ -0,0 +1,150 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Replication types, derived from a protection target's RPO.
+const (
+	ReplicationTypeSynchronous = "SYNCHRONOUS"
+	ReplicationTypeNearSync    = "NEARSYNC"
+	ReplicationTypeAsync       = "ASYNC"
+)
+
+// ProtectionPolicySpec defines the desired state of a Prism protection policy.
+type ProtectionPolicySpec struct {
+	// Name of the protection policy in Prism.
+	Name string `json:"name"`
+
+	// Description of the protection policy.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Categories select the protected VMs: a VM is protected when it carries
+	// all of these category key/value pairs.
+	// +kubebuilder:validation:MinProperties=1
+	Categories map[string]string `json:"categories"`
+
+	// Source is where the protected VMs run. Its datacenter defaults to the
+	// policy's datacenter.
+	// +optional
+	Source ProtectionLocation `json:"source,omitempty"`
+
+	// Targets are where recovery points are replicated to.
+	// +kubebuilder:validation:MinItems=1
+	Targets []ProtectionTarget `json:"targets"`
+
+	// Datacenter selects the Prism Central managing the policy from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// ProtectionLocation is an availability zone, optionally narrowed to one of
+// its clusters.
+type ProtectionLocation struct {
+	// Datacenter names the Prism Central of the availability zone from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the policy's
+	// datacenter.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// ClusterName narrows the location to a cluster. All clusters of the
+	// availability zone are used when unset.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// ProtectionTarget is a replication target of a protection policy.
+type ProtectionTarget struct {
+	ProtectionLocation `json:",inline"`
+
+	// RPO is the recovery point objective. 0 replicates synchronously, less
+	// than an hour (at least 1m) uses NearSync and an hour or more uses
+	// asynchronous replication.
+	RPO metav1.Duration `json:"rpo"`
+
+	// Retention is the number of recovery points kept.
+	Retention ProtectionRetention `json:"retention"`
+}
+
+// ProtectionRetention is the number of recovery points kept on each side.
+type ProtectionRetention struct {
+	// Local is the number of recovery points kept in the source availability zone.
+	// +kubebuilder:validation:Minimum=0
+	Local int `json:"local"`
+
+	// Remote is the number of recovery points kept in the target availability zone.
+	// +kubebuilder:validation:Minimum=1
+	Remote int `json:"remote"`
+}
+
+// ProtectionPolicyStatus defines the observed state of a Prism protection policy.
+type ProtectionPolicyStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the protection policy.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// ProtectedVMs is the number of VMs the policy currently protects.
+	// +optional
+	ProtectedVMs int `json:"protectedVms,omitempty"`
+}
+
+// A ProtectionPolicy is a Prism protection policy replicating the VMs that
+// carry its categories to other availability zones.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="VMS",type="integer",JSONPath=".status.protectedVms"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type ProtectionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProtectionPolicySpec   `json:"spec"`
+	Status ProtectionPolicyStatus `json:"status,omitempty"`
+}
+
+func (in *ProtectionPolicy) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this ProtectionPolicy.
+func (in *ProtectionPolicy) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this ProtectionPolicy.
+func (in *ProtectionPolicy) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this ProtectionPolicy.
+func (in *ProtectionPolicy) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// ProtectionPolicyList contains a list of ProtectionPolicy.
+type ProtectionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProtectionPolicy `json:"items"`
+}
+
+func (in *ProtectionPolicyList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&ProtectionPolicy{}, &ProtectionPolicyList{})
+}
@@ -0,0 +1,201 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Annotations that run a RecoveryPlan. Setting one to a new value, e.g. a
+// timestamp or change ticket, starts the corresponding job once.
+const (
+	AnnotationTestFailover        = "nutanix.crossplane.io/test-failover"
+	AnnotationTestFailoverCleanup = "nutanix.crossplane.io/test-failover-cleanup"
+)
+
+// Recovery plan job actions.
+const (
+	RecoveryPlanActionTestFailover = "TEST_FAILOVER"
+	RecoveryPlanActionCleanup      = "CLEANUP"
+)
+
+// Recovery plan job states. A job is STARTING from when it is claimed until
+// Prism returns its UUID, and UNKNOWN if the provider stopped in between.
+const (
+	RecoveryPlanJobStarting  = "STARTING"
+	RecoveryPlanJobRunning   = "RUNNING"
+	RecoveryPlanJobSucceeded = "SUCCEEDED"
+	RecoveryPlanJobFailed    = "FAILED"
+	RecoveryPlanJobUnknown   = "UNKNOWN"
+)
+
+// RecoveryPlanSpec defines the desired state of a Prism recovery plan.
+type RecoveryPlanSpec struct {
+	// Name of the recovery plan in Prism.
+	Name string `json:"name"`
+
+	// Description of the recovery plan.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// PrimaryDatacenter is where the VMs normally run, from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the plan's
+	// datacenter.
+	// +optional
+	PrimaryDatacenter string `json:"primaryDatacenter,omitempty"`
+
+	// RecoveryDatacenter is where the VMs are recovered, from the
+	// ProviderConfig's PrismCentralEndpoints. Its Prism Central must be
+	// paired with the primary one.
+	RecoveryDatacenter string `json:"recoveryDatacenter"`
+
+	// Stages are recovered in order.
+	// +kubebuilder:validation:MinItems=1
+	Stages []RecoveryPlanStage `json:"stages"`
+
+	// NetworkMappings map primary subnets to the subnets VMs are attached to
+	// after a failover or test failover.
+	// +optional
+	NetworkMappings []RecoveryPlanNetworkMapping `json:"networkMappings,omitempty"`
+
+	// Datacenter selects the Prism Central managing the plan from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// RecoveryPlanStage is a set of VMs recovered together.
+type RecoveryPlanStage struct {
+	// Entities select the VMs of the stage, which are powered on in order.
+	// +kubebuilder:validation:MinItems=1
+	Entities []RecoveryPlanEntity `json:"entities"`
+
+	// DelayAfter is how long to wait after the stage's VMs are powered on
+	// before recovering the next stage.
+	// +optional
+	DelayAfter *metav1.Duration `json:"delayAfter,omitempty"`
+}
+
+// RecoveryPlanEntity selects VMs by category or by name. Exactly one of
+// Categories and VMName must be set.
+type RecoveryPlanEntity struct {
+	// Categories select the protected VMs carrying all of these category
+	// key/value pairs.
+	// +optional
+	Categories map[string]string `json:"categories,omitempty"`
+
+	// VMName selects a single VM by name.
+	// +optional
+	VMName string `json:"vmName,omitempty"`
+}
+
+// RecoveryPlanNetworkMapping maps a primary subnet to recovery subnets.
+type RecoveryPlanNetworkMapping struct {
+	// PrimarySubnetName is the subnet in the primary datacenter.
+	PrimarySubnetName string `json:"primarySubnetName"`
+
+	// RecoverySubnetName is the subnet in the recovery datacenter used on
+	// failover.
+	RecoverySubnetName string `json:"recoverySubnetName"`
+
+	// TestSubnetName is the subnet in the recovery datacenter used on test
+	// failover. Defaults to RecoverySubnetName; an isolated subnet is
+	// recommended.
+	// +optional
+	TestSubnetName string `json:"testSubnetName,omitempty"`
+}
+
+// RecoveryPlanJobStatus is the state of a job run for the plan.
+type RecoveryPlanJobStatus struct {
+	// UUID of the job. Empty until Prism has accepted the job.
+	UUID string `json:"uuid"`
+
+	// Action is TEST_FAILOVER or CLEANUP.
+	Action string `json:"action"`
+
+	// Request is the annotation value that started the job.
+	Request string `json:"request"`
+
+	// State is STARTING, RUNNING, SUCCEEDED, FAILED or UNKNOWN.
+	State string `json:"state"`
+
+	// Message describes why the job failed or its state is unknown.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is when the job started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is when the job finished.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+// RecoveryPlanStatus defines the observed state of a Prism recovery plan.
+type RecoveryPlanStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the recovery plan.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// Jobs are the latest jobs started through annotations, one per action.
+	// +optional
+	Jobs []RecoveryPlanJobStatus `json:"jobs,omitempty"`
+}
+
+// A RecoveryPlan is a Prism recovery plan orchestrating the failover of
+// protected VMs to another datacenter.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="RECOVERY",type="string",JSONPath=".spec.recoveryDatacenter"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type RecoveryPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RecoveryPlanSpec   `json:"spec"`
+	Status RecoveryPlanStatus `json:"status,omitempty"`
+}
+
+func (in *RecoveryPlan) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this RecoveryPlan.
+func (in *RecoveryPlan) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this RecoveryPlan.
+func (in *RecoveryPlan) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this RecoveryPlan.
+func (in *RecoveryPlan) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// RecoveryPlanList contains a list of RecoveryPlan.
+type RecoveryPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RecoveryPlan `json:"items"`
+}
+
+func (in *RecoveryPlanList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&RecoveryPlan{}, &RecoveryPlanList{})
+}
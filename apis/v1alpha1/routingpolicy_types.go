@@ -0,0 +1,136 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Routing policy actions.
+const (
+	RoutingActionPermit  = "PERMIT"
+	RoutingActionDeny    = "DENY"
+	RoutingActionReroute = "REROUTE"
+)
+
+// Special routing policy endpoints.
+const (
+	// RoutingEndpointAny matches any address.
+	RoutingEndpointAny = "ANY"
+	// RoutingEndpointExternal matches addresses outside the VPC.
+	RoutingEndpointExternal = "EXTERNAL"
+)
+
+// RoutingPolicySpec defines the desired state of a VPC routing policy.
+type RoutingPolicySpec struct {
+	// Name of the routing policy in Prism.
+	Name string `json:"name"`
+
+	// VPCRef references the VPC the policy applies to. It cannot be changed
+	// after creation.
+	VPCRef xpv1.Reference `json:"vpcRef"`
+
+	// Priority of the policy. Higher priorities are evaluated first; each
+	// priority can only be used once per VPC.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=1000
+	Priority int `json:"priority"`
+
+	// Source of the matched traffic: a CIDR, ANY or EXTERNAL.
+	Source string `json:"source"`
+
+	// Destination of the matched traffic: a CIDR, ANY or EXTERNAL.
+	Destination string `json:"destination"`
+
+	// Protocol of the matched traffic. All protocols when unset.
+	// +kubebuilder:validation:Enum=TCP;UDP;ICMP
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// PortRanges of matched TCP or UDP traffic. All ports when unset.
+	// +optional
+	PortRanges []PortRange `json:"portRanges,omitempty"`
+
+	// Action taken on matched traffic.
+	// +kubebuilder:validation:Enum=PERMIT;DENY;REROUTE
+	Action string `json:"action"`
+
+	// RerouteIP is the service IP, e.g. of a firewall VM, REROUTE sends
+	// traffic to.
+	// +optional
+	RerouteIP string `json:"rerouteIp,omitempty"`
+
+	// Bidirectional also applies the policy to return traffic.
+	// +optional
+	Bidirectional bool `json:"bidirectional,omitempty"`
+
+	// Datacenter selects the Prism Central managing the VPC from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// RoutingPolicyStatus defines the observed state of a VPC routing policy.
+type RoutingPolicyStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the routing policy.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+}
+
+// A RoutingPolicy permits, denies or reroutes traffic within a VPC.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="VPC",type="string",JSONPath=".spec.vpcRef.name"
+// +kubebuilder:printcolumn:name="PRIORITY",type="integer",JSONPath=".spec.priority"
+// +kubebuilder:printcolumn:name="ACTION",type="string",JSONPath=".spec.action"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type RoutingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RoutingPolicySpec   `json:"spec"`
+	Status RoutingPolicyStatus `json:"status,omitempty"`
+}
+
+func (in *RoutingPolicy) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this RoutingPolicy.
+func (in *RoutingPolicy) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this RoutingPolicy.
+func (in *RoutingPolicy) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this RoutingPolicy.
+func (in *RoutingPolicy) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// RoutingPolicyList contains a list of RoutingPolicy.
+type RoutingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RoutingPolicy `json:"items"`
+}
+
+func (in *RoutingPolicyList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&RoutingPolicy{}, &RoutingPolicyList{})
+}
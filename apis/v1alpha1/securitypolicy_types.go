@@ -0,0 +1,183 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Security policy types.
+const (
+	// SecurityPolicyApplication restricts the traffic of the VMs selected by
+	// AppliedTo to the inbound and outbound allow lists.
+	SecurityPolicyApplication = "APPLICATION"
+	// SecurityPolicyIsolation blocks traffic between two groups of VMs.
+	SecurityPolicyIsolation = "ISOLATION"
+	// SecurityPolicyQuarantine defines the traffic still allowed to and from
+	// VMs in forensic quarantine.
+	SecurityPolicyQuarantine = "QUARANTINE"
+)
+
+// Security policy modes.
+const (
+	SecurityPolicyModeApply   = "APPLY"
+	SecurityPolicyModeMonitor = "MONITOR"
+)
+
+// SecurityPolicySpec defines the desired state of a Flow security policy.
+type SecurityPolicySpec struct {
+	// Name of the security policy in Prism.
+	Name string `json:"name"`
+
+	// Description of the security policy.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Type of the security policy. A Prism Central has a single quarantine
+	// policy, which is imported rather than created.
+	// +kubebuilder:validation:Enum=APPLICATION;ISOLATION;QUARANTINE
+	Type string `json:"type"`
+
+	// Mode is APPLY to enforce the policy or MONITOR to only log the traffic
+	// it would block.
+	// +kubebuilder:validation:Enum=APPLY;MONITOR
+	// +kubebuilder:default=MONITOR
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// AppliedTo selects the VMs of an APPLICATION policy by category.
+	// +optional
+	AppliedTo map[string]string `json:"appliedTo,omitempty"`
+
+	// Inbound lists the traffic allowed to the selected VMs of an
+	// APPLICATION or QUARANTINE policy.
+	// +optional
+	Inbound []SecurityRule `json:"inbound,omitempty"`
+
+	// AllowAllInbound allows all inbound traffic instead of Inbound.
+	// +optional
+	AllowAllInbound bool `json:"allowAllInbound,omitempty"`
+
+	// Outbound lists the traffic allowed from the selected VMs of an
+	// APPLICATION or QUARANTINE policy.
+	// +optional
+	Outbound []SecurityRule `json:"outbound,omitempty"`
+
+	// AllowAllOutbound allows all outbound traffic instead of Outbound.
+	// +optional
+	AllowAllOutbound bool `json:"allowAllOutbound,omitempty"`
+
+	// Isolation selects the two groups of VMs an ISOLATION policy separates.
+	// +optional
+	Isolation *IsolationGroups `json:"isolation,omitempty"`
+
+	// Datacenter selects the Prism Central managing the policy from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// SecurityRule allows traffic between the policy's VMs and a peer. Exactly
+// one of Categories, AddressGroupRef and CIDR selects the peer.
+type SecurityRule struct {
+	// Description of the rule.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Categories select peer VMs carrying all of these category key/value
+	// pairs.
+	// +optional
+	Categories map[string]string `json:"categories,omitempty"`
+
+	// AddressGroupRef references an AddressGroup of peer addresses.
+	// +optional
+	AddressGroupRef *xpv1.Reference `json:"addressGroupRef,omitempty"`
+
+	// CIDR is a peer IP prefix.
+	// +optional
+	CIDR string `json:"cidr,omitempty"`
+
+	// ServiceGroupRefs reference ServiceGroups of allowed services.
+	// +optional
+	ServiceGroupRefs []xpv1.Reference `json:"serviceGroupRefs,omitempty"`
+
+	// Services are allowed services in addition to ServiceGroupRefs. All
+	// services are allowed when neither is set.
+	// +optional
+	Services []NetworkService `json:"services,omitempty"`
+}
+
+// IsolationGroups are two groups of VMs selected by category.
+type IsolationGroups struct {
+	// First group of VMs.
+	// +kubebuilder:validation:MinProperties=1
+	First map[string]string `json:"first"`
+
+	// Second group of VMs.
+	// +kubebuilder:validation:MinProperties=1
+	Second map[string]string `json:"second"`
+}
+
+// SecurityPolicyStatus defines the observed state of a Flow security policy.
+type SecurityPolicyStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the security policy.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+}
+
+// A SecurityPolicy is a Flow microsegmentation policy.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="MODE",type="string",JSONPath=".spec.mode"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type SecurityPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecurityPolicySpec   `json:"spec"`
+	Status SecurityPolicyStatus `json:"status,omitempty"`
+}
+
+func (in *SecurityPolicy) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this SecurityPolicy.
+func (in *SecurityPolicy) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this SecurityPolicy.
+func (in *SecurityPolicy) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this SecurityPolicy.
+func (in *SecurityPolicy) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// SecurityPolicyList contains a list of SecurityPolicy.
+type SecurityPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecurityPolicy `json:"items"`
+}
+
+func (in *SecurityPolicyList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&SecurityPolicy{}, &SecurityPolicyList{})
+}
@@ -0,0 +1,122 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NetworkService is a protocol and its ports or ICMP types.
+type NetworkService struct {
+	// Protocol of the service.
+	// +kubebuilder:validation:Enum=TCP;UDP;ICMP
+	Protocol string `json:"protocol"`
+
+	// PortRanges of a TCP or UDP service. All ports when unset.
+	// +optional
+	PortRanges []PortRange `json:"portRanges,omitempty"`
+
+	// ICMPType of an ICMP service. All types when unset.
+	// +optional
+	ICMPType *int `json:"icmpType,omitempty"`
+
+	// ICMPCode of an ICMP service. All codes when unset.
+	// +optional
+	ICMPCode *int `json:"icmpCode,omitempty"`
+}
+
+// PortRange is an inclusive range of ports.
+type PortRange struct {
+	// Start of the range.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	Start int `json:"start"`
+
+	// End of the range. Defaults to Start.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	End int `json:"end,omitempty"`
+}
+
+// ServiceGroupSpec defines the desired state of a Flow service group.
+type ServiceGroupSpec struct {
+	// Name of the service group in Prism.
+	Name string `json:"name"`
+
+	// Description of the service group.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Services of the group.
+	// +kubebuilder:validation:MinItems=1
+	Services []NetworkService `json:"services"`
+
+	// Datacenter selects the Prism Central managing the service group from
+	// the ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in
+	// the ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// ServiceGroupStatus defines the observed state of a Flow service group.
+type ServiceGroupStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the service group.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+}
+
+// A ServiceGroup is a named set of network services used in SecurityPolicy
+// rules.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type ServiceGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceGroupSpec   `json:"spec"`
+	Status ServiceGroupStatus `json:"status,omitempty"`
+}
+
+func (in *ServiceGroup) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this ServiceGroup.
+func (in *ServiceGroup) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this ServiceGroup.
+func (in *ServiceGroup) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this ServiceGroup.
+func (in *ServiceGroup) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// ServiceGroupList contains a list of ServiceGroup.
+type ServiceGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceGroup `json:"items"`
+}
+
+func (in *ServiceGroupList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceGroup{}, &ServiceGroupList{})
+}
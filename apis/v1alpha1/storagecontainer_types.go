@@ -0,0 +1,137 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// StorageContainerSpec defines the desired state of a Prism storage
+// container.
+type StorageContainerSpec struct {
+	// Name of the storage container. VirtualMachine and VolumeGroup
+	// storageContainerName lookups match it.
+	Name string `json:"name"`
+
+	// ClusterName is the cluster the storage container is created on. It
+	// cannot be changed after creation.
+	ClusterName string `json:"clusterName"`
+
+	// ReplicationFactor is the number of copies kept of each piece of data.
+	// Defaults to the cluster's replication factor.
+	// +kubebuilder:validation:Enum=1;2;3
+	// +optional
+	ReplicationFactor int `json:"replicationFactor,omitempty"`
+
+	// Compression enables inline or, with a delay, post-process compression.
+	// +optional
+	Compression *StorageContainerCompression `json:"compression,omitempty"`
+
+	// Deduplication enables capacity deduplication.
+	// +optional
+	Deduplication bool `json:"deduplication,omitempty"`
+
+	// ErasureCoding enables erasure coding of cold data. It requires a
+	// replication factor of at least 2.
+	// +optional
+	ErasureCoding bool `json:"erasureCoding,omitempty"`
+
+	// AdvertisedCapacityGb caps the capacity the container reports to
+	// hypervisors. Unlimited when unset.
+	// +optional
+	AdvertisedCapacityGb int `json:"advertisedCapacityGb,omitempty"`
+
+	// ReservedCapacityGb is the capacity guaranteed to the container. It
+	// cannot exceed the advertised capacity.
+	// +optional
+	ReservedCapacityGb int `json:"reservedCapacityGb,omitempty"`
+
+	// Datacenter selects the Prism Central managing the cluster from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// StorageContainerCompression configures compression of a storage
+// container.
+type StorageContainerCompression struct {
+	// DelaySeconds after which written data is compressed. Data is
+	// compressed inline when 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DelaySeconds int `json:"delaySeconds,omitempty"`
+}
+
+// StorageContainerStatus defines the observed state of a Prism storage
+// container.
+type StorageContainerStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the storage container.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// UsedBytes is the logical usage of the storage container.
+	// +optional
+	UsedBytes int64 `json:"usedBytes,omitempty"`
+
+	// CapacityBytes is the capacity available to the storage container.
+	// +optional
+	CapacityBytes int64 `json:"capacityBytes,omitempty"`
+}
+
+// A StorageContainer is a Prism storage container, the datastore VM disks
+// and volume groups are placed in.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="RF",type="integer",JSONPath=".spec.replicationFactor"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type StorageContainer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StorageContainerSpec   `json:"spec"`
+	Status StorageContainerStatus `json:"status,omitempty"`
+}
+
+func (in *StorageContainer) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this StorageContainer.
+func (in *StorageContainer) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this StorageContainer.
+func (in *StorageContainer) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this StorageContainer.
+func (in *StorageContainer) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// StorageContainerList contains a list of StorageContainer.
+type StorageContainerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StorageContainer `json:"items"`
+}
+
+func (in *StorageContainerList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&StorageContainer{}, &StorageContainerList{})
+}
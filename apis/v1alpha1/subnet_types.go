@@ -0,0 +1,215 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Subnet types.
+const (
+	SubnetTypeVLAN    = "VLAN"
+	SubnetTypeOverlay = "OVERLAY"
+)
+
+// SubnetSpec defines the desired state of a Prism subnet.
+type SubnetSpec struct {
+	// Name of the subnet in Prism. VirtualMachine subnetName lookups match it.
+	Name string `json:"name"`
+
+	// Description of the subnet.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// SubnetType is VLAN for a subnet on a cluster's virtual switch or
+	// OVERLAY for a subnet in a VPC.
+	// +kubebuilder:validation:Enum=VLAN;OVERLAY
+	// +kubebuilder:default=VLAN
+	// +optional
+	SubnetType string `json:"subnetType,omitempty"`
+
+	// VLANID of a VLAN subnet. It cannot be changed after creation.
+	// +optional
+	VLANID int `json:"vlanId,omitempty"`
+
+	// ClusterName is the cluster a VLAN subnet is created on.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// External makes a VLAN subnet an external subnet connecting VPCs to the
+	// physical network. It cannot be changed after creation.
+	// +optional
+	External bool `json:"external,omitempty"`
+
+	// VPCUUID is the VPC an OVERLAY subnet is created in.
+	// +optional
+	VPCUUID string `json:"vpcUuid,omitempty"`
+
+	// VPCRef references the VPC an OVERLAY subnet is created in. It is an
+	// alternative to vpcUuid.
+	// +optional
+	VPCRef *xpv1.Reference `json:"vpcRef,omitempty"`
+
+	// IPConfig enables Prism IP address management (IPAM) for the subnet.
+	// +optional
+	IPConfig *SubnetIPConfig `json:"ipConfig,omitempty"`
+
+	// Categories are Prism category key/value pairs attached to the subnet.
+	// +optional
+	Categories map[string]string `json:"categories,omitempty"`
+
+	// NetworkProfile publishes the subnet's network details for use by
+	// VirtualMachines on it.
+	// +optional
+	NetworkProfile *NetworkProfile `json:"networkProfile,omitempty"`
+
+	// Datacenter selects the Prism Central managing the subnet from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// SubnetIPConfig defines the addressing and DHCP options of a subnet.
+type SubnetIPConfig struct {
+	// NetworkCIDR of the subnet, e.g. 192.168.1.0/24. It cannot be changed
+	// after creation.
+	NetworkCIDR string `json:"networkCidr"`
+
+	// DefaultGateway of the subnet.
+	// +optional
+	DefaultGateway string `json:"defaultGateway,omitempty"`
+
+	// Pools of addresses handed out to VMs.
+	// +optional
+	Pools []IPPool `json:"pools,omitempty"`
+
+	// DHCPOptions handed out with the addresses.
+	// +optional
+	DHCPOptions *DHCPOptions `json:"dhcpOptions,omitempty"`
+}
+
+// IPPool is an inclusive range of IP addresses.
+type IPPool struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// DHCPOptions are the DHCP options of a subnet.
+type DHCPOptions struct {
+	// DNSServers handed out to VMs.
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+
+	// DomainName handed out to VMs.
+	// +optional
+	DomainName string `json:"domainName,omitempty"`
+
+	// DomainSearch list handed out to VMs.
+	// +optional
+	DomainSearch []string `json:"domainSearch,omitempty"`
+}
+
+// NetworkProfile defines where a subnet's network details are published.
+// They are written as network-<name>.json to a ConfigMap, in the format the
+// provider reads from /etc/provider, so mounting that ConfigMap into the
+// provider makes the Subnet the single source of truth for them.
+type NetworkProfile struct {
+	// ConfigMapRef is the ConfigMap the network details are written to.
+	ConfigMapRef ConfigMapReference `json:"configMapRef"`
+
+	// AllowedRepos restricts the subnet to VirtualMachines whose repo label
+	// matches one of these values. All repos are allowed when empty.
+	// +optional
+	AllowedRepos []string `json:"allowedRepos,omitempty"`
+
+	// Extra values published with the network details, e.g. email,
+	// puppet_master or foreman_host.
+	// +optional
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// ConfigMapReference is a reference to a ConfigMap.
+type ConfigMapReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// SubnetStatus defines the observed state of a Prism subnet.
+type SubnetStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the subnet.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// FreeIPs is the number of unassigned addresses in the subnet's pools.
+	// +optional
+	FreeIPs int `json:"freeIps,omitempty"`
+
+	// NetworkProfile is where the subnet's network details were last
+	// published, so they can be removed after a rename or on deletion.
+	// +optional
+	NetworkProfile *PublishedNetworkProfile `json:"networkProfile,omitempty"`
+}
+
+// PublishedNetworkProfile is the ConfigMap entry holding a subnet's network
+// details.
+type PublishedNetworkProfile struct {
+	ConfigMapRef ConfigMapReference `json:"configMapRef"`
+	Key          string             `json:"key"`
+}
+
+// A Subnet is a Prism VLAN or overlay subnet.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.subnetType"
+// +kubebuilder:printcolumn:name="CIDR",type="string",JSONPath=".spec.ipConfig.networkCidr"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type Subnet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubnetSpec   `json:"spec"`
+	Status SubnetStatus `json:"status,omitempty"`
+}
+
+func (in *Subnet) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this Subnet.
+func (in *Subnet) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this Subnet.
+func (in *Subnet) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this Subnet.
+func (in *Subnet) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// SubnetList contains a list of Subnet.
+type SubnetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Subnet `json:"items"`
+}
+
+func (in *SubnetList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&Subnet{}, &SubnetList{})
+}
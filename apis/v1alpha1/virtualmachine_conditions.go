@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// Condition types reported by a VirtualMachine in addition to the standard
+// Crossplane Ready and Synced conditions.
+const (
+	// TypeValidated indicates whether the VM spec passed ProviderConfig
+	// policy checks (LoB, datacenter, availability zone).
+	TypeValidated xpv1.ConditionType = "Validated"
+
+	// TypePlaced indicates whether the VM has been placed on a cluster and
+	// its image and subnet references have been resolved.
+	TypePlaced xpv1.ConditionType = "Placed"
+
+	// TypeGuestReady indicates whether the guest operating system is usable.
+	TypeGuestReady xpv1.ConditionType = "GuestReady"
+)
+
+// Reasons a VirtualMachine condition is in its current state.
+const (
+	ReasonValidationPassed xpv1.ConditionReason = "ValidationPassed"
+	ReasonPolicyRejected   xpv1.ConditionReason = "PolicyRejected"
+	ReasonPlaced           xpv1.ConditionReason = "PlacedOnCluster"
+	ReasonPlacementFailed  xpv1.ConditionReason = "PlacementFailed"
+	ReasonGuestUnknown     xpv1.ConditionReason = "GuestStatusUnknown"
+	ReasonGuestReady       xpv1.ConditionReason = "GuestReady"
+	ReasonWaitingForIP     xpv1.ConditionReason = "WaitingForIP"
+	ReasonWaitingForProbe  xpv1.ConditionReason = "WaitingForProbe"
+	ReasonGuestTimedOut    xpv1.ConditionReason = "GuestReadinessTimedOut"
+)
+
+// Validated returns a condition indicating the VM spec passed policy checks.
+func Validated() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeValidated,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonValidationPassed,
+	}
+}
+
+// ValidationFailed returns a condition indicating the VM spec was rejected by
+// ProviderConfig policy.
+func ValidationFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeValidated,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPolicyRejected,
+		Message:            err.Error(),
+	}
+}
+
+// Placed returns a condition indicating the VM was placed on cluster.
+func Placed(cluster string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePlaced,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlaced,
+		Message:            "placed on cluster " + cluster,
+	}
+}
+
+// PlacementFailed returns a condition indicating the VM's cluster, image or
+// subnet could not be resolved.
+func PlacementFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePlaced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPlacementFailed,
+		Message:            err.Error(),
+	}
+}
+
+// GuestUnknown returns a condition indicating guest readiness has not been
+// determined.
+func GuestUnknown() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeGuestReady,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonGuestUnknown,
+	}
+}
+
+// GuestReady returns a condition indicating the guest OS is usable.
+func GuestReady(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeGuestReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonGuestReady,
+		Message:            msg,
+	}
+}
+
+// GuestNotReady returns a condition indicating the guest OS is not yet, or
+// no longer, usable for the supplied reason.
+func GuestNotReady(reason xpv1.ConditionReason, msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeGuestReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            msg,
+	}
+}
+
+// GetCondition of this VirtualMachine.
+func (in *VirtualMachine) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this VirtualMachine.
+func (in *VirtualMachine) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// GetWriteConnectionSecretToReference of this VirtualMachine.
+func (in *VirtualMachine) GetWriteConnectionSecretToReference() *xpv1.SecretReference {
+	return in.Spec.WriteConnectionSecretToReference
+}
+
+// SetWriteConnectionSecretToReference of this VirtualMachine.
+func (in *VirtualMachine) SetWriteConnectionSecretToReference(r *xpv1.SecretReference) {
+	in.Spec.WriteConnectionSecretToReference = r
+}
+
+// GetPublishConnectionDetailsTo of this VirtualMachine.
+func (in *VirtualMachine) GetPublishConnectionDetailsTo() *xpv1.PublishConnectionDetailsTo {
+	return in.Spec.PublishConnectionDetailsTo
+}
+
+// SetPublishConnectionDetailsTo of this VirtualMachine.
+func (in *VirtualMachine) SetPublishConnectionDetailsTo(r *xpv1.PublishConnectionDetailsTo) {
+	in.Spec.PublishConnectionDetailsTo = r
+}
@@ -24,14 +24,542 @@ type VirtualMachineSpec struct {
 	ImageName        string            `json:"imageName,omitempty"`
 	AdditionalDisks  []DiskSpec        `json:"additionalDisks,omitempty"`
 	ExternalFacts    map[string]string `json:"externalFacts,omitempty"`
+
+	// BootDisk configures the disk cloned from the image. Without it the
+	// boot disk has the image's size and is placed on SCSI 0 in the
+	// cluster's default storage container.
+	// +optional
+	BootDisk *BootDiskSpec `json:"bootDisk,omitempty"`
+
+	// Boot configures the VM's firmware, machine type and boot order.
+	// Without it Prism's defaults are used and boot settings are not
+	// checked for drift.
+	// +optional
+	Boot *BootConfig `json:"boot,omitempty"`
+
+	// VTPM adds a virtual TPM to the VM. It requires UEFI firmware.
+	// +optional
+	VTPM bool `json:"vtpm,omitempty"`
+
+	// CPU configures how NumVCPUs are presented to the guest and which
+	// host CPU features it sees. Without it Prism's defaults are used and
+	// these settings are not checked for drift.
+	// +optional
+	CPU *CPUConfig `json:"cpu,omitempty"`
+
+	// Memory configures memory overcommit and hot-plug. Without it Prism's
+	// defaults are used and these settings are not checked for drift.
+	// +optional
+	Memory *MemoryConfig `json:"memory,omitempty"`
+
+	// VPCName restricts subnetName lookups to the OVERLAY subnets of the
+	// named VPC, telling apart tenant subnets with the same name.
+	// +optional
+	VPCName string `json:"vpcName,omitempty"`
+
+	// FloatingIP assigns a floating IP to the VM's first NIC. Its address is
+	// published with the connection details.
+	// +optional
+	FloatingIP *VMFloatingIP `json:"floatingIp,omitempty"`
+
+	// AntiAffinityGroup keeps the VM on a different host of its cluster
+	// than the other VMs in the same group, e.g. the replicas of a service.
+	// It must be a valid category value.
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	AntiAffinityGroup string `json:"antiAffinityGroup,omitempty"`
+
+	// ProjectRef references the Project resource the VM is placed in.
+	// +optional
+	ProjectRef *xpv1.Reference `json:"projectRef,omitempty"`
+
+	// ProjectName is the name of the Prism project the VM is placed in. When
+	// neither it nor ProjectRef is set, the project mapped to the VM's LoB by
+	// the ProviderConfig is used.
+	// +optional
+	ProjectName string `json:"projectName,omitempty"`
+
+	// ProjectUUID is the UUID of the Prism project, resolved from ProjectRef
+	// or ProjectName when unset.
+	// +optional
+	ProjectUUID string `json:"projectUuid,omitempty"`
+
+	// Protection places the VM under a ProtectionPolicy by giving it the
+	// categories the policy selects.
+	// +optional
+	Protection *VMProtection `json:"protection,omitempty"`
+
+	// RestoreFrom creates the VM by restoring a recovery point instead of
+	// from an image. It only applies when the VM is created.
+	// +optional
+	RestoreFrom *RestoreSource `json:"restoreFrom,omitempty"`
+
+	// Source creates the VM by cloning an existing VM or deploying a VM
+	// template instead of from an image. The VM's sizing, CPU and memory
+	// settings, NIC, categories and guest customization are applied on top
+	// of the source's; unset numVcpus and memorySizeMib keep the source's
+	// values. It only applies when the VM is created.
+	// +optional
+	Source *VMSource `json:"source,omitempty"`
+
+	// VolumeGroupRefs references VolumeGroup resources attached to the VM.
+	// Volume groups removed from the list are detached.
+	// +optional
+	VolumeGroupRefs []xpv1.Reference `json:"volumeGroupRefs,omitempty"`
+
+	// Categories are Prism category key/value pairs attached to the VM.
+	// +optional
+	Categories map[string]string `json:"categories,omitempty"`
+
+	// PowerState is the desired power state of the VM.
+	// +kubebuilder:validation:Enum=ON;OFF
+	// +optional
+	PowerState string `json:"powerState,omitempty"`
+
+	// DriftPolicy controls how changes made to the VM outside Crossplane
+	// (e.g. in the Prism UI) are handled. Drift is enforced by default.
+	// +optional
+	DriftPolicy *DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// GuestCredentials configures the administrator account set up in the
+	// guest and published with the VM's connection details.
+	// +optional
+	GuestCredentials *GuestCredentials `json:"guestCredentials,omitempty"`
+
+	// GuestTools manages Nutanix Guest Tools (NGT) on the VM.
+	// +optional
+	GuestTools *GuestTools `json:"guestTools,omitempty"`
+
+	// GuestReadiness gates the Ready condition on the guest OS being usable.
+	// When unset the VM is Ready as soon as Prism reports it.
+	// +optional
+	GuestReadiness *GuestReadiness `json:"guestReadiness,omitempty"`
+
+	// WriteConnectionSecretToReference specifies the namespace and name of a
+	// Secret to which the VM's connection details (IP addresses, hostname,
+	// FQDN and credentials) are written.
+	// +optional
+	WriteConnectionSecretToReference *xpv1.SecretReference `json:"writeConnectionSecretToRef,omitempty"`
+
+	// PublishConnectionDetailsTo specifies the connection secret config which
+	// contains a name, metadata and a reference to a secret store config to
+	// which the connection details are written. Requires the
+	// EnableAlphaExternalSecretStores feature flag.
+	// +optional
+	PublishConnectionDetailsTo *xpv1.PublishConnectionDetailsTo `json:"publishConnectionDetailsTo,omitempty"`
+}
+
+// VMFloatingIP requests a floating IP for a VM in a VPC.
+type VMFloatingIP struct {
+	// ExternalSubnetName is the external subnet the address is allocated
+	// from. Changing it has no effect on an assigned floating IP.
+	ExternalSubnetName string `json:"externalSubnetName"`
+}
+
+// VMFloatingIPStatus is the floating IP assigned to a VM.
+type VMFloatingIPStatus struct {
+	UUID    string `json:"uuid"`
+	Address string `json:"address,omitempty"`
+}
+
+// VMSubnetStatus is the subnet a VM's subnetName resolved to.
+type VMSubnetStatus struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+// VMProtection binds a VM to a protection policy.
+type VMProtection struct {
+	// PolicyRef references the ProtectionPolicy protecting the VM.
+	PolicyRef xpv1.Reference `json:"policyRef"`
+}
+
+// VMProtectionStatus is the observed replication state of a VM.
+type VMProtectionStatus struct {
+	// Policy is the name of the Prism protection policy protecting the VM.
+	Policy string `json:"policy,omitempty"`
+
+	// State is the protection state reported by Prism, e.g. PROTECTED or
+	// UNPROTECTED.
+	State string `json:"state,omitempty"`
+
+	// ReplicationType is SYNCHRONOUS, NEARSYNC or ASYNC.
+	ReplicationType string `json:"replicationType,omitempty"`
+
+	// LastReplicationTime is when the latest recovery point was replicated.
+	LastReplicationTime *metav1.Time `json:"lastReplicationTime,omitempty"`
 }
 
+// RestoreSource selects the recovery point a VM is restored from.
+type RestoreSource struct {
+	// SnapshotRef references a VirtualMachineSnapshot in the VM's namespace.
+	// +optional
+	SnapshotRef *xpv1.Reference `json:"snapshotRef,omitempty"`
+
+	// RecoveryPointUUID is the UUID of a recovery point not managed by the
+	// provider.
+	// +optional
+	RecoveryPointUUID string `json:"recoveryPointUuid,omitempty"`
+}
+
+// VMSource selects the VM or VM template a VM is cloned from. Exactly one of
+// VM, Template and TemplateRef must be set.
+type VMSource struct {
+	// VM is the name or UUID of the Prism VM to clone. The clone is placed
+	// on the source VM's cluster.
+	// +optional
+	VM string `json:"vm,omitempty"`
+
+	// Template is the name of the Prism VM template to deploy.
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// TemplateRef references a VMTemplate in the VM's namespace to deploy.
+	// +optional
+	TemplateRef *xpv1.Reference `json:"templateRef,omitempty"`
+
+	// Version is the name of the template version to deploy. Defaults to
+	// the template's active version.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// GuestCredentials defines the administrator credentials for a VM's guest OS.
+type GuestCredentials struct {
+	// Username of the administrator account.
+	// +kubebuilder:default=nutanix
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// PasswordSecretRef references the administrator password, in the
+	// VirtualMachine's namespace. A random password is generated when unset.
+	// +optional
+	PasswordSecretRef *xpv1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// SSHPublicKey is authorized for the administrator account.
+	// +optional
+	SSHPublicKey string `json:"sshPublicKey,omitempty"`
+
+	// GenerateSSHKey generates an SSH key pair when SSHPublicKey is unset and
+	// publishes the private key with the connection details.
+	// +optional
+	GenerateSSHKey bool `json:"generateSshKey,omitempty"`
+}
+
+// NGT capabilities.
+const (
+	GuestToolsCapabilityVSSSnapshot        = "VSS_SNAPSHOT"
+	GuestToolsCapabilitySelfServiceRestore = "SELF_SERVICE_RESTORE"
+)
+
+// GuestTools defines the desired Nutanix Guest Tools configuration of a VM.
+type GuestTools struct {
+	// Enabled enables NGT for the VM.
+	Enabled bool `json:"enabled"`
+
+	// Mount mounts the NGT installer ISO on the VM until NGT is installed.
+	// It requires an empty CD-ROM in AdditionalDisks.
+	// +optional
+	Mount bool `json:"mount,omitempty"`
+
+	// Capabilities to enable: VSS_SNAPSHOT for application consistent
+	// snapshots and SELF_SERVICE_RESTORE for in-guest file restore.
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// GuestToolsStatus is the observed Nutanix Guest Tools state of a VM.
+type GuestToolsStatus struct {
+	Enabled      bool     `json:"enabled"`
+	ISOMounted   bool     `json:"isoMounted,omitempty"`
+	Installed    bool     `json:"installed"`
+	Version      string   `json:"version,omitempty"`
+	Reachable    bool     `json:"reachable"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// GuestReadiness defines how the controller decides a VM's guest is usable.
+type GuestReadiness struct {
+	// WaitForIP waits until NGT or Prism reports an IP address on the NIC
+	// selected by NICIndex. Implied by TCPPort and HTTPGet.
+	// +optional
+	WaitForIP bool `json:"waitForIp,omitempty"`
+
+	// NICIndex selects the NIC whose IP address is checked.
+	// +kubebuilder:default=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NICIndex int `json:"nicIndex,omitempty"`
+
+	// TCPPort waits until a TCP connection to this port on the guest IP succeeds.
+	// +optional
+	TCPPort int `json:"tcpPort,omitempty"`
+
+	// HTTPGet waits until an HTTP GET against the guest IP returns a 2xx or 3xx status.
+	// +optional
+	HTTPGet *HTTPGetCheck `json:"httpGet,omitempty"`
+
+	// Timeout is how long after creation to wait before giving up and
+	// reporting the guest as not ready. Defaults to 15m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// HTTPGetCheck defines an HTTP health endpoint on the guest.
+type HTTPGetCheck struct {
+	// Path to request.
+	// +kubebuilder:default="/"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Port to connect to.
+	Port int `json:"port"`
+
+	// Scheme is HTTP or HTTPS. Certificates are not verified.
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +kubebuilder:default=HTTP
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// Drift policy modes.
+const (
+	// DriftModeEnforce reverts drifted fields to the desired state.
+	DriftModeEnforce = "Enforce"
+	// DriftModeReportOnly reports drifted fields in status without changing the VM.
+	DriftModeReportOnly = "ReportOnly"
+)
+
+// Fields compared during drift detection.
+const (
+	DriftFieldNumVCPUs      = "numVcpus"
+	DriftFieldMemorySizeMiB = "memorySizeMib"
+	DriftFieldDisks         = "disks"
+	DriftFieldNICs          = "nics"
+	DriftFieldCategories    = "categories"
+	DriftFieldPowerState    = "powerState"
+	DriftFieldBoot          = "boot"
+	DriftFieldCPU           = "cpu"
+	DriftFieldMemory        = "memory"
+)
+
+// DriftPolicy defines how out-of-band changes to a VM are handled.
+type DriftPolicy struct {
+	// Mode is Enforce to correct drift or ReportOnly to only report it.
+	// +kubebuilder:validation:Enum=Enforce;ReportOnly
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// IgnoreFields lists fields that are neither reported nor corrected.
+	// Valid values are numVcpus, memorySizeMib, disks, nics, categories,
+	// powerState, boot, cpu and memory.
+	// +optional
+	IgnoreFields []string `json:"ignoreFields,omitempty"`
+}
+
+// FieldDrift describes a field whose observed value differs from the desired value.
+type FieldDrift struct {
+	Field    string `json:"field"`
+	Desired  string `json:"desired"`
+	Observed string `json:"observed"`
+}
+
+// Disk device types.
+const (
+	DeviceTypeDisk  = "DISK"
+	DeviceTypeCDROM = "CDROM"
+)
+
+// Disk bus types.
+const (
+	DiskBusSCSI = "SCSI"
+	DiskBusSATA = "SATA"
+	DiskBusPCI  = "PCI"
+	DiskBusIDE  = "IDE"
+)
+
 // DiskSpec defines the disk configuration for a Nutanix VM.
 type DiskSpec struct {
-	DeviceIndex int    `json:"deviceIndex"`
-	SizeGb      int    `json:"sizeGb"`
-	ImageUUID   string `json:"imageUuid,omitempty"`
-	ImageName   string `json:"imageName,omitempty"`
+	DeviceIndex int `json:"deviceIndex"`
+
+	// SizeGb of a disk. A disk cloned from an image is grown to it and
+	// defaults to the image size. CD-ROMs have no size.
+	// +optional
+	SizeGb int `json:"sizeGb,omitempty"`
+
+	ImageUUID string `json:"imageUuid,omitempty"`
+	ImageName string `json:"imageName,omitempty"`
+
+	// DeviceType is DISK (the default) or CDROM. A CD-ROM mounts the ISO
+	// image set by imageName or imageUuid, or is left empty.
+	// +kubebuilder:validation:Enum=DISK;CDROM
+	// +optional
+	DeviceType string `json:"deviceType,omitempty"`
+
+	// Bus the device is attached to. Defaults to SCSI for disks and IDE for
+	// CD-ROMs, which only support IDE and SATA.
+	// +kubebuilder:validation:Enum=SCSI;SATA;PCI;IDE
+	// +optional
+	Bus string `json:"bus,omitempty"`
+
+	// StorageContainerRef references the StorageContainer the disk is
+	// placed in. It must be on the VM's cluster.
+	// +optional
+	StorageContainerRef *xpv1.Reference `json:"storageContainerRef,omitempty"`
+
+	// StorageContainerName is the storage container of the VM's cluster the
+	// disk is placed in. Defaults to the cluster's default container.
+	// +optional
+	StorageContainerName string `json:"storageContainerName,omitempty"`
+
+	// StorageContainerUUID is the UUID of the storage container, resolved
+	// from StorageContainerRef or StorageContainerName when unset.
+	// +optional
+	StorageContainerUUID string `json:"storageContainerUuid,omitempty"`
+
+	// FlashMode pins the disk's data to the SSD tier. It requires a cluster
+	// with hybrid storage.
+	// +optional
+	FlashMode bool `json:"flashMode,omitempty"`
+}
+
+// BootDiskSpec configures the disk cloned from a VM's image, attached at
+// device index 0 of its bus.
+type BootDiskSpec struct {
+	// SizeGb the boot disk is grown to after it is cloned from the image. It
+	// cannot be smaller than the image and defaults to the image size.
+	// +optional
+	SizeGb int `json:"sizeGb,omitempty"`
+
+	// Bus the boot disk is attached to. Defaults to SCSI.
+	// +kubebuilder:validation:Enum=SCSI;SATA;PCI;IDE
+	// +optional
+	Bus string `json:"bus,omitempty"`
+
+	// StorageContainerRef references the StorageContainer the boot disk is
+	// placed in. It must be on the VM's cluster.
+	// +optional
+	StorageContainerRef *xpv1.Reference `json:"storageContainerRef,omitempty"`
+
+	// StorageContainerName is the storage container of the VM's cluster the
+	// boot disk is placed in. Defaults to the cluster's default container.
+	// +optional
+	StorageContainerName string `json:"storageContainerName,omitempty"`
+
+	// StorageContainerUUID is the UUID of the storage container, resolved
+	// from StorageContainerRef or StorageContainerName when unset.
+	// +optional
+	StorageContainerUUID string `json:"storageContainerUuid,omitempty"`
+
+	// FlashMode pins the boot disk's data to the SSD tier. It requires a
+	// cluster with hybrid storage.
+	// +optional
+	FlashMode bool `json:"flashMode,omitempty"`
+}
+
+// Boot firmware types.
+const (
+	BootTypeLegacy = "LEGACY"
+	BootTypeUEFI   = "UEFI"
+)
+
+// Machine types.
+const (
+	MachineTypePC  = "PC"
+	MachineTypeQ35 = "Q35"
+)
+
+// Boot devices of a legacy boot order.
+const (
+	BootDeviceCDROM   = "CDROM"
+	BootDeviceDisk    = "DISK"
+	BootDeviceNetwork = "NETWORK"
+)
+
+// BootConfig configures how a VM boots.
+type BootConfig struct {
+	// Type is the VM's firmware, LEGACY BIOS or UEFI. Defaults to LEGACY.
+	// +kubebuilder:validation:Enum=LEGACY;UEFI
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// SecureBoot enables UEFI Secure Boot. It requires the UEFI type and
+	// the Q35 machine type, and no devices on the IDE bus.
+	// +optional
+	SecureBoot bool `json:"secureBoot,omitempty"`
+
+	// MachineType is the emulated chipset, PC (i440fx) or Q35. Defaults to PC.
+	// +kubebuilder:validation:Enum=PC;Q35
+	// +optional
+	MachineType string `json:"machineType,omitempty"`
+
+	// DeviceOrder is the order in which boot devices are tried. It is only
+	// supported with LEGACY firmware; UEFI VMs keep their boot order in
+	// NVRAM. Defaults to CDROM, DISK, NETWORK.
+	// +kubebuilder:validation:items:Enum=CDROM;DISK;NETWORK
+	// +optional
+	DeviceOrder []string `json:"deviceOrder,omitempty"`
+}
+
+// VMBootStatus is the observed boot configuration of a VM.
+type VMBootStatus struct {
+	Type        string   `json:"type,omitempty"`
+	SecureBoot  bool     `json:"secureBoot,omitempty"`
+	MachineType string   `json:"machineType,omitempty"`
+	DeviceOrder []string `json:"deviceOrder,omitempty"`
+	VTPM        bool     `json:"vtpm,omitempty"`
+}
+
+// CPUConfig configures the CPU topology and features of a VM.
+type CPUConfig struct {
+	// Sockets is the number of virtual sockets. Sockets times
+	// CoresPerSocket must equal NumVCPUs. Defaults to NumVCPUs divided by
+	// CoresPerSocket.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Sockets int `json:"sockets,omitempty"`
+
+	// CoresPerSocket is the number of cores of each socket. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CoresPerSocket int `json:"coresPerSocket,omitempty"`
+
+	// ThreadsPerCore is the number of threads of each core. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2
+	// +optional
+	ThreadsPerCore int `json:"threadsPerCore,omitempty"`
+
+	// NUMANodes is the number of virtual NUMA nodes the sockets and memory
+	// are split across. Both must divide evenly between the nodes. Zero
+	// disables vNUMA.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NUMANodes int `json:"numaNodes,omitempty"`
+
+	// Passthrough exposes the host CPU model and features to the guest. It
+	// prevents live migration to hosts with different CPUs.
+	// +optional
+	Passthrough bool `json:"passthrough,omitempty"`
+
+	// HardwareVirtualization exposes the CPU's virtualization extensions to
+	// the guest so it can run nested hypervisors.
+	// +optional
+	HardwareVirtualization bool `json:"hardwareVirtualization,omitempty"`
+}
+
+// MemoryConfig configures the memory features of a VM.
+type MemoryConfig struct {
+	// Overcommit lets the host reclaim memory the guest is not using. It
+	// is not supported with vNUMA or CPU passthrough.
+	// +optional
+	Overcommit bool `json:"overcommit,omitempty"`
+
+	// HotPlug allows memorySizeMib to be increased while the VM is running.
+	// It is not supported with vNUMA.
+	// +optional
+	HotPlug bool `json:"hotPlug,omitempty"`
 }
 
 // VirtualMachineStatus defines the observed state of a Nutanix VM.
@@ -39,10 +567,68 @@ type VirtualMachineStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
 	VMID                   string `json:"vmId,omitempty"`
 	State                  string `json:"state,omitempty"`
+
+	// Drift lists fields that differed from the desired state at the last
+	// observation. Fields corrected under the Enforce mode are cleared once
+	// Prism reports the desired value.
+	// +optional
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GuestTools is the observed Nutanix Guest Tools state.
+	// +optional
+	GuestTools *GuestToolsStatus `json:"guestTools,omitempty"`
+
+	// GuestIP is the IP address of the NIC selected for guest readiness
+	// checks, or of the first NIC reporting one.
+	// +optional
+	GuestIP string `json:"guestIp,omitempty"`
+
+	// VolumeGroups are the UUIDs of the volume groups attached to the VM
+	// through VolumeGroupRefs.
+	// +optional
+	VolumeGroups []string `json:"volumeGroups,omitempty"`
+
+	// Protection is the observed replication state of the VM.
+	// +optional
+	Protection *VMProtectionStatus `json:"protection,omitempty"`
+
+	// FloatingIP is the floating IP assigned through spec.floatingIp.
+	// +optional
+	FloatingIP *VMFloatingIPStatus `json:"floatingIp,omitempty"`
+
+	// AntiAffinityGroup is the anti-affinity group whose policy the VM was
+	// added to.
+	// +optional
+	AntiAffinityGroup string `json:"antiAffinityGroup,omitempty"`
+
+	// Subnet is the subnet subnetName resolved to when the VM was created or
+	// adopted. The VM's NIC is compared against it rather than against the
+	// latest subnet matching subnetName, as long as it still matches.
+	// +optional
+	Subnet *VMSubnetStatus `json:"subnet,omitempty"`
+
+	// Boot is the observed boot configuration of the VM.
+	// +optional
+	Boot *VMBootStatus `json:"boot,omitempty"`
+
+	// CreationTime is when the VM was created or adopted by the provider.
+	// Guest readiness timeouts are measured from it.
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+
+	// LastDriftCheckTime is when drift was last evaluated.
+	// +optional
+	LastDriftCheckTime *metav1.Time `json:"lastDriftCheckTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PLACED",type="string",JSONPath=".status.conditions[?(@.type=='Placed')].status",priority=1
+// +kubebuilder:printcolumn:name="VM-ID",type="string",JSONPath=".status.vmId"
+// +kubebuilder:printcolumn:name="IP",type="string",JSONPath=".status.guestIp",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 
 type VirtualMachine struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -74,3 +660,7 @@ var (
 	SchemeBuilder      = &scheme.Builder{GroupVersion: SchemeGroupVersion}
 	AddToScheme        = SchemeBuilder.AddToScheme
 )
+
+func init() {
+	SchemeBuilder.Register(&VirtualMachine{}, &VirtualMachineList{})
+}
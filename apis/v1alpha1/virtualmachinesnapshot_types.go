@@ -0,0 +1,135 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Snapshot consistency levels.
+const (
+	// ConsistencyCrash snapshots the VM's disks as they are.
+	ConsistencyCrash = "CRASH_CONSISTENT"
+	// ConsistencyApplication quiesces the guest through NGT before
+	// snapshotting. Requires the VSS_SNAPSHOT guest tools capability.
+	ConsistencyApplication = "APPLICATION_CONSISTENT"
+)
+
+// Recovery point states.
+const (
+	RecoveryPointStateComplete = "COMPLETE"
+	RecoveryPointStateExpired  = "EXPIRED"
+)
+
+// VirtualMachineSnapshotSpec defines the desired state of a VM recovery point.
+type VirtualMachineSnapshotSpec struct {
+	// VMRef references the VirtualMachine to snapshot, in the same
+	// namespace. The VM must have been created.
+	VMRef xpv1.Reference `json:"vmRef"`
+
+	// Name of the recovery point in Prism. Defaults to the resource name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Consistency of the snapshot.
+	// +kubebuilder:validation:Enum=CRASH_CONSISTENT;APPLICATION_CONSISTENT
+	// +kubebuilder:default=CRASH_CONSISTENT
+	// +optional
+	Consistency string `json:"consistency,omitempty"`
+
+	// ExpiresAfter is how long after creation Prism deletes the recovery
+	// point. The recovery point never expires when unset.
+	// +optional
+	ExpiresAfter *metav1.Duration `json:"expiresAfter,omitempty"`
+
+	// Datacenter selects the Prism Central managing the VM. It must match the
+	// VM's datacenter.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// VirtualMachineSnapshotStatus defines the observed state of a VM recovery point.
+type VirtualMachineSnapshotStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the recovery point.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// VMUUID is the UUID of the snapshotted VM.
+	// +optional
+	VMUUID string `json:"vmUuid,omitempty"`
+
+	// State of the recovery point, e.g. COMPLETE, or EXPIRED once Prism has
+	// deleted it.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Consistency is the consistency level Prism achieved.
+	// +optional
+	Consistency string `json:"consistency,omitempty"`
+
+	// CreationTime is when the recovery point was taken.
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+
+	// ExpirationTime is when Prism deletes the recovery point.
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// A VirtualMachineSnapshot is a Prism recovery point of a VirtualMachine,
+// which VirtualMachines can be restored from through spec.restoreFrom.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="VM",type="string",JSONPath=".spec.vmRef.name"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="EXPIRES",type="string",JSONPath=".status.expirationTime"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,nutanix}
+type VirtualMachineSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualMachineSnapshotSpec   `json:"spec"`
+	Status VirtualMachineSnapshotStatus `json:"status,omitempty"`
+}
+
+func (in *VirtualMachineSnapshot) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this VirtualMachineSnapshot.
+func (in *VirtualMachineSnapshot) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this VirtualMachineSnapshot.
+func (in *VirtualMachineSnapshot) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this VirtualMachineSnapshot.
+func (in *VirtualMachineSnapshot) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// VirtualMachineSnapshotList contains a list of VirtualMachineSnapshot.
+type VirtualMachineSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualMachineSnapshot `json:"items"`
+}
+
+func (in *VirtualMachineSnapshotList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&VirtualMachineSnapshot{}, &VirtualMachineSnapshotList{})
+}
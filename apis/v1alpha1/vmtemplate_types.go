@@ -0,0 +1,128 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// VMTemplateSpec defines the desired state of a Prism VM template.
+type VMTemplateSpec struct {
+	// Name of the template in Prism. VirtualMachine source.template
+	// lookups match it.
+	Name string `json:"name"`
+
+	// Description of the template.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// SourceVMRef references the golden VirtualMachine, in the same
+	// namespace, that versions are published from. The VM must have been
+	// created.
+	// +optional
+	SourceVMRef *xpv1.Reference `json:"sourceVmRef,omitempty"`
+
+	// SourceVMUUID is the UUID of a golden VM not managed by the provider.
+	// +optional
+	SourceVMUUID string `json:"sourceVmUuid,omitempty"`
+
+	// Version is the name of the template's active version. Changing it
+	// publishes a new version from the golden VM's current state, or makes
+	// an existing version of that name active again.
+	Version string `json:"version"`
+
+	// VersionDescription describes the changes of a newly published
+	// version.
+	// +optional
+	VersionDescription string `json:"versionDescription,omitempty"`
+
+	// MaxVersions is the number of versions kept. The oldest inactive
+	// versions are deleted when a new one is published. All versions are
+	// kept when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxVersions int `json:"maxVersions,omitempty"`
+
+	// Datacenter selects the Prism Central managing the template. It must
+	// match the golden VirtualMachine's datacenter.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// VMTemplateVersion is a published version of a VM template.
+type VMTemplateVersion struct {
+	Name string `json:"name"`
+	UUID string `json:"uuid"`
+}
+
+// VMTemplateStatus defines the observed state of a Prism VM template.
+type VMTemplateStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the template.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// ActiveVersion is the name of the version VMs are deployed from by
+	// default.
+	// +optional
+	ActiveVersion string `json:"activeVersion,omitempty"`
+
+	// Versions lists the template's versions, oldest first.
+	// +optional
+	Versions []VMTemplateVersion `json:"versions,omitempty"`
+}
+
+// A VMTemplate is a Prism VM template published from a golden VM, which
+// VirtualMachines can be deployed from through source.templateRef.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.activeVersion"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,nutanix}
+type VMTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VMTemplateSpec   `json:"spec"`
+	Status VMTemplateStatus `json:"status,omitempty"`
+}
+
+func (in *VMTemplate) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this VMTemplate.
+func (in *VMTemplate) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this VMTemplate.
+func (in *VMTemplate) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this VMTemplate.
+func (in *VMTemplate) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// VMTemplateList contains a list of VMTemplate.
+type VMTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VMTemplate `json:"items"`
+}
+
+func (in *VMTemplateList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&VMTemplate{}, &VMTemplateList{})
+}
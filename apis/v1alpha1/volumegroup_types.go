@@ -0,0 +1,156 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// VolumeGroupSpec defines the desired state of a Prism volume group.
+type VolumeGroupSpec struct {
+	// Name of the volume group in Prism.
+	Name string `json:"name"`
+
+	// Description of the volume group.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ClusterName is the cluster the volume group is created on. It cannot be
+	// changed after creation.
+	ClusterName string `json:"clusterName"`
+
+	// StorageContainerName is the storage container of disks that do not
+	// name their own.
+	// +optional
+	StorageContainerName string `json:"storageContainerName,omitempty"`
+
+	// Disks of the volume group. Disks can be added and grown but not
+	// shrunk or removed.
+	// +optional
+	Disks []VolumeGroupDisk `json:"disks,omitempty"`
+
+	// Shared allows the volume group to be attached to several VMs at once,
+	// e.g. for clustered databases.
+	// +optional
+	Shared bool `json:"shared,omitempty"`
+
+	// FlashMode pins the volume group's data to the SSD tier.
+	// +optional
+	FlashMode bool `json:"flashMode,omitempty"`
+
+	// ISCSI configures access to the volume group by external iSCSI
+	// initiators.
+	// +optional
+	ISCSI *VolumeGroupISCSI `json:"iscsi,omitempty"`
+
+	// Datacenter selects the Prism Central managing the volume group from
+	// the ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in
+	// the ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// VolumeGroupDisk is a disk of a volume group.
+type VolumeGroupDisk struct {
+	// Index of the disk within the volume group.
+	Index int `json:"index"`
+
+	// SizeGb is the size of the disk in GiB.
+	SizeGb int `json:"sizeGb"`
+
+	// StorageContainerName overrides the volume group's storage container.
+	// +optional
+	StorageContainerName string `json:"storageContainerName,omitempty"`
+}
+
+// VolumeGroupISCSI defines the iSCSI settings of a volume group.
+type VolumeGroupISCSI struct {
+	// TargetPrefix is prepended to the generated iSCSI target name.
+	// +optional
+	TargetPrefix string `json:"targetPrefix,omitempty"`
+
+	// Initiators lists the IQNs of the external initiators allowed to
+	// connect.
+	// +optional
+	Initiators []string `json:"initiators,omitempty"`
+
+	// LoadBalanceVMAttachments spreads the volume group's disks across CVMs.
+	// +optional
+	LoadBalanceVMAttachments bool `json:"loadBalanceVmAttachments,omitempty"`
+
+	// ChapSecretRef references the target CHAP secret required from
+	// initiators.
+	// +optional
+	ChapSecretRef *xpv1.SecretKeySelector `json:"chapSecretRef,omitempty"`
+}
+
+// VolumeGroupStatus defines the observed state of a Prism volume group.
+type VolumeGroupStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the volume group.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// ISCSITargetName is the iSCSI target name of the volume group.
+	// +optional
+	ISCSITargetName string `json:"iscsiTargetName,omitempty"`
+
+	// AttachedVMs are the UUIDs of the VMs the volume group is attached to.
+	// +optional
+	AttachedVMs []string `json:"attachedVms,omitempty"`
+}
+
+// A VolumeGroup is a Prism volume group, a set of disks that can be attached
+// to VMs or exposed to external hosts over iSCSI.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="CLUSTER",type="string",JSONPath=".spec.clusterName"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type VolumeGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeGroupSpec   `json:"spec"`
+	Status VolumeGroupStatus `json:"status,omitempty"`
+}
+
+func (in *VolumeGroup) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this VolumeGroup.
+func (in *VolumeGroup) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this VolumeGroup.
+func (in *VolumeGroup) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this VolumeGroup.
+func (in *VolumeGroup) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeGroupList contains a list of VolumeGroup.
+type VolumeGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VolumeGroup `json:"items"`
+}
+
+func (in *VolumeGroupList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&VolumeGroup{}, &VolumeGroupList{})
+}
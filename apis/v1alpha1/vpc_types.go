@@ -0,0 +1,108 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// VPCSpec defines the desired state of a Flow Virtual Networking VPC.
+type VPCSpec struct {
+	// Name of the VPC in Prism. VirtualMachine vpcName lookups match it.
+	Name string `json:"name"`
+
+	// Description of the VPC.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ExternalSubnetNames are the external subnets connecting the VPC to the
+	// physical network. Floating IPs and SNAT use their addresses.
+	// +optional
+	ExternalSubnetNames []string `json:"externalSubnetNames,omitempty"`
+
+	// ExternallyRoutablePrefixes are VPC prefixes routed without NAT through
+	// a no-NAT external subnet.
+	// +optional
+	ExternallyRoutablePrefixes []string `json:"externallyRoutablePrefixes,omitempty"`
+
+	// DNSServers handed out to VMs in the VPC's subnets.
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+
+	// Categories are Prism category key/value pairs attached to the VPC,
+	// e.g. the LoB owning it.
+	// +optional
+	Categories map[string]string `json:"categories,omitempty"`
+
+	// Datacenter selects the Prism Central managing the VPC from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// VPCStatus defines the observed state of a VPC.
+type VPCStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the VPC.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// SNATIPs are the external addresses the VPC's traffic is translated to.
+	// +optional
+	SNATIPs []string `json:"snatIps,omitempty"`
+}
+
+// A VPC is a Flow Virtual Networking virtual private cloud, an isolated
+// network for OVERLAY subnets.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type VPC struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VPCSpec   `json:"spec"`
+	Status VPCStatus `json:"status,omitempty"`
+}
+
+func (in *VPC) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this VPC.
+func (in *VPC) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this VPC.
+func (in *VPC) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this VPC.
+func (in *VPC) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// VPCList contains a list of VPC.
+type VPCList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VPC `json:"items"`
+}
+
+func (in *VPCList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&VPC{}, &VPCList{})
+}
@@ -0,0 +1,137 @@
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// VPNGatewaySpec defines the desired state of a VPC VPN gateway.
+type VPNGatewaySpec struct {
+	// Name of the VPN gateway in Prism.
+	Name string `json:"name"`
+
+	// VPCRef references the VPC the gateway connects. It cannot be changed
+	// after creation.
+	VPCRef xpv1.Reference `json:"vpcRef"`
+
+	// ClusterName is the cluster the gateway VM runs on. It cannot be
+	// changed after creation.
+	ClusterName string `json:"clusterName"`
+
+	// LocalASN is the BGP autonomous system number of the gateway. Static
+	// routing is used when unset.
+	// +optional
+	LocalASN int64 `json:"localAsn,omitempty"`
+
+	// Connections are IPsec tunnels to remote gateways.
+	// +optional
+	Connections []VPNConnection `json:"connections,omitempty"`
+
+	// Datacenter selects the Prism Central managing the VPC from the
+	// ProviderConfig's PrismCentralEndpoints. Defaults to the endpoint in the
+	// ProviderConfig credentials.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// VPNConnection is an IPsec tunnel to a remote gateway.
+type VPNConnection struct {
+	// Name of the connection.
+	Name string `json:"name"`
+
+	// PeerIP is the public address of the remote gateway.
+	PeerIP string `json:"peerIp"`
+
+	// PreSharedKeySecretRef references the IPsec pre-shared key.
+	PreSharedKeySecretRef xpv1.SecretKeySelector `json:"preSharedKeySecretRef"`
+
+	// RemoteASN is the BGP autonomous system number of the remote gateway.
+	// Required when the gateway uses BGP.
+	// +optional
+	RemoteASN int64 `json:"remoteAsn,omitempty"`
+
+	// RemoteCIDRs are the prefixes statically routed through the tunnel when
+	// the gateway does not use BGP.
+	// +optional
+	RemoteCIDRs []string `json:"remoteCidrs,omitempty"`
+}
+
+// VPNConnectionStatus is the observed state of a VPN connection.
+type VPNConnectionStatus struct {
+	Name string `json:"name"`
+
+	// State of the IPsec tunnel, e.g. UP or DOWN.
+	State string `json:"state"`
+}
+
+// VPNGatewayStatus defines the observed state of a VPC VPN gateway.
+type VPNGatewayStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// UUID of the VPN gateway.
+	// +optional
+	UUID string `json:"uuid,omitempty"`
+
+	// PublicIP is the address remote gateways connect to.
+	// +optional
+	PublicIP string `json:"publicIp,omitempty"`
+
+	// Connections are the observed states of the gateway's connections.
+	// +optional
+	Connections []VPNConnectionStatus `json:"connections,omitempty"`
+}
+
+// A VPNGateway connects a VPC to remote networks over IPsec.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="VPC",type="string",JSONPath=".spec.vpcRef.name"
+// +kubebuilder:printcolumn:name="PUBLIC-IP",type="string",JSONPath=".status.publicIp"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,nutanix}
+type VPNGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VPNGatewaySpec   `json:"spec"`
+	Status VPNGatewayStatus `json:"status,omitempty"`
+}
+
+func (in *VPNGateway) DeepCopyObject() runtime.Object {
+	return in
+}
+
+// GetDatacenter returns the datacenter whose Prism Central manages this VPNGateway.
+func (in *VPNGateway) GetDatacenter() string {
+	return in.Spec.Datacenter
+}
+
+// GetCondition of this VPNGateway.
+func (in *VPNGateway) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this VPNGateway.
+func (in *VPNGateway) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// VPNGatewayList contains a list of VPNGateway.
+type VPNGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VPNGateway `json:"items"`
+}
+
+func (in *VPNGatewayList) DeepCopyObject() runtime.Object {
+	return in
+}
+
+func init() {
+	SchemeBuilder.Register(&VPNGateway{}, &VPNGatewayList{})
+}
@@ -48,6 +48,12 @@ type ProviderConfigSpec struct {
 	// +optional
 	IsLoBMandatory bool `json:"isLobMandatory,omitempty"`
 
+	// LoBProjects maps LoB values to the Prism project their VMs are placed
+	// in. VMs of a mapped LoB are placed in its project by default and are
+	// rejected if they name a different one.
+	// +optional
+	LoBProjects map[string]string `json:"lobProjects,omitempty"`
+
 	// PrismCentralEndpoints maps datacenter names to their Prism Central endpoints.
 	// This allows dynamic selection of the Prism Central based on the datacenter specified in the VM spec.
 	// +optional
@@ -67,6 +73,33 @@ type ProviderConfigSpec struct {
 	// If specified and the feature is enabled, this will be used to map availabilityZone to clusterName in VM specs.
 	// +optional
 	AvailabilityZoneMappingURL string `json:"availabilityZoneMappingURL,omitempty"`
+
+	// CategoryRules derive Prism categories for VMs from their LoB, labels
+	// and external facts.
+	// +optional
+	CategoryRules *CategoryRules `json:"categoryRules,omitempty"`
+}
+
+// CategoryRules define how Prism categories are derived for VMs. Categories
+// set explicitly in a VM's spec take precedence over derived ones.
+type CategoryRules struct {
+	// LoBCategory is the category key whose value is set to the VM's LoB.
+	// +optional
+	LoBCategory string `json:"lobCategory,omitempty"`
+
+	// Labels maps Kubernetes label keys on the VM to category keys.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ExternalFacts maps VM external fact keys to category keys.
+	// +optional
+	ExternalFacts map[string]string `json:"externalFacts,omitempty"`
+
+	// CreateMissingValues creates category values that do not exist in Prism
+	// yet. When false, VMs using a missing value are rejected. Category keys
+	// are never created.
+	// +optional
+	CreateMissingValues bool `json:"createMissingValues,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -17,34 +17,96 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"math"
 	"os"
 	"path/filepath"
 
+	"golang.org/x/time/rate"
 	"gopkg.in/alecthomas/kingpin.v2"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	xpcontroller "github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/mgeorge67701/provider-nutanix/apis"
 	"github.com/mgeorge67701/provider-nutanix/internal/controller"
+	"github.com/mgeorge67701/provider-nutanix/internal/features"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
 )
 
 func main() {
 	app := kingpin.New(filepath.Base(os.Args[0]), "Nutanix provider for Crossplane.").DefaultEnvars()
 	debug := app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+	otlpEndpoint := app.Flag("otlp-endpoint", "OTLP/HTTP collector host:port to export traces to. Tracing is disabled when empty.").String()
+	otlpInsecure := app.Flag("otlp-insecure", "Export traces over plain HTTP instead of HTTPS.").Bool()
+
+	syncPeriod := app.Flag("sync", "How often all resources will be double-checked for drift from the desired state.").Short('s').Default("1h").Duration()
+	pollInterval := app.Flag("poll", "How often individual resources will be checked for drift from the desired state.").Default("1m").Duration()
+	maxConcurrentReconciles := app.Flag("max-concurrent-reconciles", "The number of resources each controller may reconcile concurrently.").Default("10").Int()
+	prismRateLimit := app.Flag("prism-rate-limit", "Maximum Prism API requests per second across all controllers. Zero disables the limit.").Default("0").Float64()
+
+	leaderElection := app.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").Bool()
+	leaderElectionNamespace := app.Flag("leader-election-namespace", "Namespace in which the leader election lease is created. Defaults to the namespace the provider runs in.").String()
+	namespace := app.Flag("namespace", "Only watch resources in this namespace. Watches all namespaces when empty.").String()
+	enableExternalSecretStores := app.Flag("enable-external-secret-stores", "Enable support for publishing connection details to external secret stores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
+	healthProbeAddr := app.Flag("health-probe-bind-address", "The address the health and readiness probe endpoint binds to.").Default(":8081").String()
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
 	zl := zap.New(zap.UseDevMode(*debug))
 	log := logging.NewLogrLogger(zl.WithName("provider-nutanix"))
 	ctrl.SetLogger(zl)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), *otlpEndpoint, *otlpInsecure)
+	kingpin.FatalIfError(err, "Cannot setup tracing")
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Info("Cannot flush traces", "error", err)
+		}
+	}()
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
-	mgr, err := ctrl.NewManager(cfg, ctrl.Options{})
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		SyncPeriod: syncPeriod,
+		Namespace:  *namespace,
+		// Credential Secrets and network profile ConfigMaps usually live
+		// outside the watched namespace, e.g. in crossplane-system, which
+		// the namespaced cache cannot read.
+		ClientDisableCacheFor:   []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}},
+		LeaderElection:          *leaderElection,
+		LeaderElectionID:        "crossplane-leader-election-provider-nutanix",
+		LeaderElectionNamespace: *leaderElectionNamespace,
+		HealthProbeBindAddress:  *healthProbeAddr,
+	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
+	kingpin.FatalIfError(mgr.AddHealthzCheck("healthz", healthz.Ping), "Cannot add health check")
+	kingpin.FatalIfError(mgr.AddReadyzCheck("readyz", healthz.Ping), "Cannot add readiness check")
+
+	o := controller.Options{
+		Options: xpcontroller.Options{
+			Logger:                  log,
+			PollInterval:            *pollInterval,
+			MaxConcurrentReconciles: *maxConcurrentReconciles,
+			Features:                &feature.Flags{},
+		},
+	}
+	if *enableExternalSecretStores {
+		o.Features.Enable(features.EnableAlphaExternalSecretStores)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaExternalSecretStores)
+	}
+	if *prismRateLimit > 0 {
+		o.PrismRateLimiter = rate.NewLimiter(rate.Limit(*prismRateLimit), int(math.Max(1, math.Ceil(*prismRateLimit))))
+	}
+
+	log.Debug("Starting", "sync-period", syncPeriod.String(), "poll-interval", pollInterval.String(), "max-concurrent-reconciles", *maxConcurrentReconciles, "prism-rate-limit", *prismRateLimit)
 
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Nutanix APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log), "Cannot setup Nutanix controllers")
+	kingpin.FatalIfError(controller.Setup(mgr, o), "Cannot setup Nutanix controllers")
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// ownerCategoryKey is the Prism category recording which managed resource
+// owns a VM. Its value is the UID of the owning VirtualMachine.
+const ownerCategoryKey = "CrossplaneOwner"
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// adopt takes ownership of the existing Prism VM named by the VirtualMachine's
+// external-name annotation, either a VM UUID or an exact VM name. Unset spec
+// fields are late-initialized from the observed VM, which is otherwise left
+// unchanged apart from its ownership category.
+func (r *VirtualMachineReconciler) adopt(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine, externalName string) (err error) {
+	ctx, span := tracing.Start(ctx, "AdoptVM", attribute.String("crossplane.external_name", externalName))
+	defer func() { tracing.End(span, err) }()
+
+	observed, err := findVM(ctx, ntxCli, externalName)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(tracing.AttrVMUUID.String(observed.UUID))
+
+	owner := string(vm.GetUID())
+	if current, ok := observed.Categories[ownerCategoryKey]; ok && current != owner {
+		return fmt.Errorf("VM %s (%s) is already owned by another managed resource (%s=%s)", observed.Name, observed.UUID, ownerCategoryKey, current)
+	}
+
+	if fields := lateInitialize(&vm.Spec, observed); len(fields) > 0 {
+		patch, err := json.Marshal(map[string]interface{}{"spec": fields})
+		if err != nil {
+			return err
+		}
+		// Keep the spec resolved during this reconcile, such as the project
+		// selectProject chose, and the pending status; Patch overwrites both
+		// with what the API server returns.
+		spec, status := vm.Spec, vm.Status
+		if err := r.Patch(ctx, vm, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			return err
+		}
+		vm.Spec, vm.Status = spec, status
+	}
+
+	if err := ntxCli.EnsureCategoryValue(ctx, ownerCategoryKey, owner); err != nil {
+		return err
+	}
+	// Only the ownership category is attached here. The rest of the spec is
+	// not resolved yet, and converging the VM on it is left to drift
+	// handling and its policy.
+	if err := ntxCli.UpdateVMCategories(ctx, observed.UUID, map[string]string{ownerCategoryKey: owner}, nil); err != nil {
+		return err
+	}
+
+	vm.Status.VMID = observed.UUID
+	vm.Status.State = "Adopted"
+	now := metav1.Now()
+	vm.Status.CreationTime = &now
+	r.record.Event(vm, event.Normal(reasonAdopted, fmt.Sprintf("Adopted existing VM %s (%s)", observed.Name, observed.UUID)))
+	return nil
+}
+
+// findVM looks up a VM by UUID or, failing that, by exact name.
+func findVM(ctx context.Context, ntxCli *nutanix.Client, externalName string) (*nutanix.VMInfo, error) {
+	if uuidPattern.MatchString(externalName) {
+		observed, err := ntxCli.GetVM(ctx, externalName)
+		if err != nil {
+			return nil, err
+		}
+		if observed != nil {
+			return observed, nil
+		}
+	}
+	vms, err := ntxCli.FindVMsByName(ctx, externalName)
+	if err != nil {
+		return nil, err
+	}
+	switch len(vms) {
+	case 0:
+		return nil, fmt.Errorf("no VM found with UUID or name %q", externalName)
+	case 1:
+		return &vms[0], nil
+	default:
+		return nil, fmt.Errorf("%d VMs are named %q; use a VM UUID instead", len(vms), externalName)
+	}
+}
+
+// lateInitialize copies observed values into unset spec fields and returns
+// the fields it set, keyed by their JSON name.
+func lateInitialize(spec *v1alpha1.VirtualMachineSpec, observed *nutanix.VMInfo) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if spec.Name == "" && observed.Name != "" {
+		spec.Name = observed.Name
+		fields["name"] = spec.Name
+	}
+	if spec.NumVCPUs == 0 && observed.NumVCPUs != 0 {
+		spec.NumVCPUs = observed.NumVCPUs
+		fields["numVcpus"] = spec.NumVCPUs
+	}
+	if spec.MemorySizeMiB == 0 && observed.MemorySizeMiB != 0 {
+		spec.MemorySizeMiB = observed.MemorySizeMiB
+		fields["memorySizeMib"] = spec.MemorySizeMiB
+	}
+	if spec.ClusterName == "" && spec.AvailabilityZone == "" && observed.ClusterName != "" {
+		spec.ClusterName = observed.ClusterName
+		fields["clusterName"] = spec.ClusterName
+	}
+	if spec.SubnetUUID == "" && spec.SubnetName == "" && len(observed.NICs) > 0 {
+		spec.SubnetUUID = observed.NICs[0].SubnetUUID
+		fields["subnetUuid"] = spec.SubnetUUID
+	}
+	if spec.AdditionalDisks == nil {
+		spec.AdditionalDisks = observedAdditionalDisks(spec.BootDisk, observed.Disks)
+		if spec.AdditionalDisks != nil {
+			fields["additionalDisks"] = spec.AdditionalDisks
+		}
+	}
+	if spec.Categories == nil {
+		for k, v := range observed.Categories {
+			if k == ownerCategoryKey || k == antiAffinityCategoryKey {
+				continue
+			}
+			if spec.Categories == nil {
+				spec.Categories = map[string]string{}
+			}
+			spec.Categories[k] = v
+		}
+		if spec.Categories != nil {
+			fields["categories"] = spec.Categories
+		}
+	}
+	if spec.PowerState == "" && observed.PowerState != "" {
+		spec.PowerState = observed.PowerState
+		fields["powerState"] = spec.PowerState
+	}
+	if group := observed.Categories[antiAffinityCategoryKey]; spec.AntiAffinityGroup == "" && group != "" {
+		spec.AntiAffinityGroup = group
+		fields["antiAffinityGroup"] = group
+	}
+	return fields
+}
+
+// observedAdditionalDisks converts the observed disks other than the boot
+// disk described by boot into additional disk specs.
+func observedAdditionalDisks(boot *v1alpha1.BootDiskSpec, disks []nutanix.VMDiskInfo) []v1alpha1.DiskSpec {
+	var specs []v1alpha1.DiskSpec
+	for _, d := range disks {
+		if isBootDisk(boot, d) {
+			continue
+		}
+		disk := v1alpha1.DiskSpec{
+			DeviceIndex:          d.DeviceIndex,
+			DeviceType:           d.DeviceType,
+			Bus:                  d.Bus,
+			StorageContainerUUID: d.StorageContainerUUID,
+			FlashMode:            d.FlashMode,
+		}
+		if d.DeviceType == v1alpha1.DeviceTypeCDROM {
+			disk.ImageUUID = d.ImageUUID
+		} else {
+			disk.SizeGb = d.SizeGb
+		}
+		specs = append(specs, disk)
+	}
+	return specs
+}
+
+// withOwnerCategory returns the VM's spec with the ownership category added,
+// as sent to Prism on create and update.
+func withOwnerCategory(vm *v1alpha1.VirtualMachine) v1alpha1.VirtualMachineSpec {
+	spec := vm.Spec
+	spec.Categories = make(map[string]string, len(vm.Spec.Categories)+1)
+	for k, v := range vm.Spec.Categories {
+		spec.Categories[k] = v
+	}
+	spec.Categories[ownerCategoryKey] = string(vm.GetUID())
+	return spec
+}
+
+// setExternalName records the VM UUID in the external-name annotation so the
+// VM is re-adopted rather than re-created if its status is lost.
+func (r *VirtualMachineReconciler) setExternalName(ctx context.Context, vm *v1alpha1.VirtualMachine, uuid string) error {
+	if meta.GetExternalName(vm) == uuid {
+		return nil
+	}
+	meta.SetExternalName(vm, uuid)
+	return r.updateCriticalAnnotations(ctx, vm)
+}
+
+// updateCriticalAnnotations persists the VM's annotations, retrying on API
+// errors, as losing the external name would orphan the Prism VM. The latest
+// object is updated instead of vm, so that the spec resolved and the status
+// recorded during this reconcile are kept.
+func (r *VirtualMachineReconciler) updateCriticalAnnotations(ctx context.Context, vm *v1alpha1.VirtualMachine) error {
+	annotations := vm.GetAnnotations()
+	return retry.OnError(retry.DefaultRetry, resource.IsAPIError, func() error {
+		var latest v1alpha1.VirtualMachine
+		if err := r.Get(ctx, client.ObjectKeyFromObject(vm), &latest); err != nil {
+			return err
+		}
+		meta.AddAnnotations(&latest, annotations)
+		if err := r.Update(ctx, &latest); err != nil {
+			return err
+		}
+		vm.SetResourceVersion(latest.GetResourceVersion())
+		return nil
+	})
+}
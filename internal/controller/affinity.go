@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// antiAffinityCategoryKey is the Prism category whose values select the VMs
+// of each anti-affinity group.
+const antiAffinityCategoryKey = "CrossplaneAntiAffinityGroup"
+
+// antiAffinityPolicyName is the name of the VM-VM anti-affinity policy of
+// group.
+func antiAffinityPolicyName(group string) string {
+	return "crossplane-" + group
+}
+
+// applyAntiAffinityGroup adds the VM's anti-affinity group category to its
+// spec, from which it is set on new VMs and updated on existing ones through
+// updateManagedCategories, and ensures the group's category value and VM-VM
+// anti-affinity policy exist. When the VM leaves a group, the group's policy is deleted unless
+// other VMs are still in it.
+func (r *VirtualMachineReconciler) applyAntiAffinityGroup(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	group := vm.Spec.AntiAffinityGroup
+	if previous := vm.Status.AntiAffinityGroup; previous != "" && previous != group {
+		if err := r.releaseAntiAffinityGroup(ctx, ntxCli, vm); err != nil {
+			return err
+		}
+	}
+	if group == "" {
+		return nil
+	}
+	if v, ok := vm.Spec.Categories[antiAffinityCategoryKey]; ok && v != group {
+		return fmt.Errorf("category %s is managed through antiAffinityGroup", antiAffinityCategoryKey)
+	}
+	// The spec's map is shared with the informer cache, so it is copied
+	// rather than written to.
+	categories := make(map[string]string, len(vm.Spec.Categories)+1)
+	for k, v := range vm.Spec.Categories {
+		categories[k] = v
+	}
+	categories[antiAffinityCategoryKey] = group
+	vm.Spec.Categories = categories
+	if vm.Status.AntiAffinityGroup == group {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "ApplyAntiAffinityGroup")
+	defer func() { tracing.End(span, err) }()
+
+	if err := ntxCli.EnsureCategoryValue(ctx, antiAffinityCategoryKey, group); err != nil {
+		return fmt.Errorf("cannot create category value %s=%s: %w", antiAffinityCategoryKey, group, err)
+	}
+	_, err = ntxCli.FindVMAntiAffinityPolicy(ctx, antiAffinityPolicyName(group))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		_, err = ntxCli.PutVMAntiAffinityPolicy(ctx, nutanix.VMAntiAffinityPolicyInfo{
+			Name:        antiAffinityPolicyName(group),
+			Description: fmt.Sprintf("Spreads the VMs of anti-affinity group %s across hosts", group),
+			Categories:  map[string]string{antiAffinityCategoryKey: group},
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("cannot create anti-affinity policy of group %s: %w", group, err)
+	}
+	vm.Status.AntiAffinityGroup = group
+	r.record.Event(vm, event.Normal(reasonAntiAffinityUpdated, fmt.Sprintf("Added to anti-affinity group %s", group)))
+	return nil
+}
+
+// releaseAntiAffinityGroup removes the VM from the anti-affinity group in its
+// status, deleting the group's policy when no other VirtualMachine on the
+// same Prism Central is in or joining it.
+func (r *VirtualMachineReconciler) releaseAntiAffinityGroup(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) error {
+	group := vm.Status.AntiAffinityGroup
+	if group == "" {
+		return nil
+	}
+	var vms v1alpha1.VirtualMachineList
+	if err := r.List(ctx, &vms); err != nil {
+		return fmt.Errorf("cannot list VirtualMachines: %w", err)
+	}
+	inUse := false
+	for _, other := range vms.Items {
+		if other.GetUID() != vm.GetUID() && other.Spec.Datacenter == vm.Spec.Datacenter && (other.Spec.AntiAffinityGroup == group || other.Status.AntiAffinityGroup == group) {
+			inUse = true
+			break
+		}
+	}
+	if !inUse {
+		policy, err := ntxCli.FindVMAntiAffinityPolicy(ctx, antiAffinityPolicyName(group))
+		if err == nil && policy != nil {
+			err = ntxCli.DeleteVMAntiAffinityPolicy(ctx, policy.UUID)
+		}
+		if err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+			return fmt.Errorf("cannot delete anti-affinity policy of group %s: %w", group, err)
+		}
+	}
+	vm.Status.AntiAffinityGroup = ""
+	r.record.Event(vm, event.Normal(reasonAntiAffinityUpdated, fmt.Sprintf("Removed from anti-affinity group %s", group)))
+	return nil
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// validateBoot checks the VM's boot settings against each other and its
+// devices and, before the VM is created, against what its image requires.
+func validateBoot(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) error {
+	boot := v1alpha1.BootConfig{}
+	if vm.Spec.Boot != nil {
+		boot = *vm.Spec.Boot
+	}
+	uefi := boot.Type == v1alpha1.BootTypeUEFI
+	if boot.SecureBoot && (!uefi || boot.MachineType != v1alpha1.MachineTypeQ35) {
+		return fmt.Errorf("boot.secureBoot requires boot type UEFI and machine type Q35")
+	}
+	if vm.Spec.VTPM && !uefi {
+		return fmt.Errorf("vtpm requires boot type UEFI")
+	}
+	if uefi && len(boot.DeviceOrder) > 0 {
+		return fmt.Errorf("boot.deviceOrder is only supported with boot type LEGACY")
+	}
+	seen := map[string]bool{}
+	for _, d := range boot.DeviceOrder {
+		if seen[d] {
+			return fmt.Errorf("boot.deviceOrder lists %s more than once", d)
+		}
+		seen[d] = true
+	}
+	if vm.Spec.BootDisk != nil && vm.Spec.BootDisk.Bus == v1alpha1.DiskBusIDE && uefi {
+		return fmt.Errorf("UEFI VMs cannot boot from a disk on the IDE bus")
+	}
+	if boot.SecureBoot {
+		for i, d := range vm.Spec.AdditionalDisks {
+			if nutanix.DiskBus(d.Bus, d.DeviceType) == v1alpha1.DiskBusIDE {
+				return fmt.Errorf("additionalDisks[%d]: Secure Boot VMs cannot have devices on the IDE bus", i)
+			}
+		}
+	}
+
+	if vm.Status.VMID != "" || vm.Spec.ImageUUID == "" {
+		return nil
+	}
+	images, err := ntxCli.ListImages(ctx)
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		if img.UUID != vm.Spec.ImageUUID {
+			continue
+		}
+		switch {
+		case img.RequiresUEFI && !uefi:
+			return fmt.Errorf("image %s requires boot type UEFI", img.Name)
+		case img.RequiresSecureBoot && !boot.SecureBoot:
+			return fmt.Errorf("image %s requires boot.secureBoot", img.Name)
+		case img.RequiresVTPM && !vm.Spec.VTPM:
+			return fmt.Errorf("image %s requires vtpm", img.Name)
+		}
+		return nil
+	}
+	return nil
+}
+
+// formatBoot renders the desired and observed boot settings as
+// "type/machineType" followed by "+secureBoot", "+vtpm" and, when the
+// desired boot order is set, the boot order. Unset types and machine types
+// default to LEGACY and PC.
+func formatBoot(boot *v1alpha1.BootConfig, vtpm bool, observed nutanix.VMBootInfo) (desired, actual string) {
+	if boot == nil {
+		boot = &v1alpha1.BootConfig{}
+	}
+	desired = formatBootSettings(boot.Type, boot.MachineType, boot.SecureBoot, vtpm, boot.DeviceOrder)
+	var order []string
+	if len(boot.DeviceOrder) > 0 {
+		order = observed.DeviceOrder
+	}
+	actual = formatBootSettings(observed.Type, observed.MachineType, observed.SecureBoot, observed.VTPM, order)
+	return desired, actual
+}
+
+func formatBootSettings(bootType, machineType string, secureBoot, vtpm bool, order []string) string {
+	if bootType == "" {
+		bootType = v1alpha1.BootTypeLegacy
+	}
+	if machineType == "" {
+		machineType = v1alpha1.MachineTypePC
+	}
+	s := bootType + "/" + machineType
+	if secureBoot {
+		s += "+secureBoot"
+	}
+	if vtpm {
+		s += "+vtpm"
+	}
+	if len(order) > 0 {
+		s += ":" + strings.Join(order, ">")
+	}
+	return s
+}
+
+// bootStatus converts the observed boot configuration of a VM to its status.
+func bootStatus(b nutanix.VMBootInfo) *v1alpha1.VMBootStatus {
+	if b.Type == "" {
+		return nil
+	}
+	return &v1alpha1.VMBootStatus{
+		Type:        b.Type,
+		SecureBoot:  b.SecureBoot,
+		MachineType: b.MachineType,
+		DeviceOrder: b.DeviceOrder,
+		VTPM:        b.VTPM,
+	}
+}
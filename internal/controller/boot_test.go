@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+func TestValidateBoot(t *testing.T) {
+	secure := &v1alpha1.BootConfig{Type: v1alpha1.BootTypeUEFI, SecureBoot: true, MachineType: v1alpha1.MachineTypeQ35}
+
+	cases := map[string]struct {
+		reason string
+		vm     v1alpha1.VirtualMachine
+		want   string
+	}{
+		"Default": {
+			reason: "A VM without boot settings boots legacy BIOS.",
+			vm:     v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{ImageUUID: "img-uuid-1"}},
+		},
+		"LegacyDeviceOrder": {
+			reason: "Legacy VMs can set their boot order.",
+			vm: v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{
+				Boot: &v1alpha1.BootConfig{DeviceOrder: []string{v1alpha1.BootDeviceCDROM, v1alpha1.BootDeviceDisk}},
+			}},
+		},
+		"SecureBootWithoutQ35": {
+			reason: "Secure Boot requires the Q35 machine type.",
+			vm: v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{
+				Boot: &v1alpha1.BootConfig{Type: v1alpha1.BootTypeUEFI, SecureBoot: true},
+			}},
+			want: "boot.secureBoot requires boot type UEFI and machine type Q35",
+		},
+		"SecureBootWithoutUEFI": {
+			reason: "Secure Boot requires UEFI.",
+			vm: v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{
+				Boot: &v1alpha1.BootConfig{SecureBoot: true, MachineType: v1alpha1.MachineTypeQ35},
+			}},
+			want: "boot.secureBoot requires boot type UEFI and machine type Q35",
+		},
+		"VTPMWithoutUEFI": {
+			reason: "A vTPM requires UEFI.",
+			vm:     v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{VTPM: true}},
+			want:   "vtpm requires boot type UEFI",
+		},
+		"UEFIDeviceOrder": {
+			reason: "UEFI VMs cannot set a boot order.",
+			vm: v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{
+				Boot: &v1alpha1.BootConfig{Type: v1alpha1.BootTypeUEFI, DeviceOrder: []string{v1alpha1.BootDeviceDisk}},
+			}},
+			want: "boot.deviceOrder is only supported with boot type LEGACY",
+		},
+		"DuplicateDevice": {
+			reason: "Each device is listed once in the boot order.",
+			vm: v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{
+				Boot: &v1alpha1.BootConfig{DeviceOrder: []string{v1alpha1.BootDeviceDisk, v1alpha1.BootDeviceDisk}},
+			}},
+			want: "boot.deviceOrder lists DISK more than once",
+		},
+		"UEFIFromIDE": {
+			reason: "UEFI VMs cannot boot from an IDE disk.",
+			vm: v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{
+				Boot:     &v1alpha1.BootConfig{Type: v1alpha1.BootTypeUEFI},
+				BootDisk: &v1alpha1.BootDiskSpec{Bus: v1alpha1.DiskBusIDE},
+			}},
+			want: "UEFI VMs cannot boot from a disk on the IDE bus",
+		},
+		"SecureBootWithIDECDROM": {
+			reason: "Secure Boot VMs cannot have IDE devices, including CD-ROMs on the default bus.",
+			vm: v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{
+				Boot:            secure,
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeCDROM}},
+			}},
+			want: "additionalDisks[0]: Secure Boot VMs cannot have devices on the IDE bus",
+		},
+		"ImageRequiresUEFI": {
+			reason: "New VMs must meet the boot requirements of their image.",
+			vm:     v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{ImageUUID: "img-uuid-4"}},
+			want:   "image win2022-2025-01 requires boot type UEFI",
+		},
+		"ImageRequiresVTPM": {
+			reason: "Each of the image's requirements is checked.",
+			vm:     v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{ImageUUID: "img-uuid-4", Boot: secure}},
+			want:   "image win2022-2025-01 requires vtpm",
+		},
+		"ImageRequirementsMet": {
+			reason: "A VM meeting its image's requirements is accepted.",
+			vm:     v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{ImageUUID: "img-uuid-4", Boot: secure, VTPM: true}},
+		},
+		"ExistingVM": {
+			reason: "The image's requirements are only checked before the VM is created.",
+			vm: v1alpha1.VirtualMachine{
+				Spec:   v1alpha1.VirtualMachineSpec{ImageUUID: "img-uuid-4"},
+				Status: v1alpha1.VirtualMachineStatus{VMID: "vm-uuid-1"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ntxCli := nutanix.NewClient("https://prism.example.com:9440", "admin", "secret", false)
+			err := validateBoot(context.Background(), ntxCli, &tc.vm)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidateBoot(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFormatBoot(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		boot        *v1alpha1.BootConfig
+		vtpm        bool
+		observed    nutanix.VMBootInfo
+		wantDesired string
+		wantActual  string
+	}{
+		"Defaults": {
+			reason:      "Unset types default to LEGACY and PC on both sides.",
+			wantDesired: "LEGACY/PC",
+			wantActual:  "LEGACY/PC",
+		},
+		"SecureBootAndVTPM": {
+			reason:      "Secure Boot and vTPM are appended when enabled.",
+			boot:        &v1alpha1.BootConfig{Type: v1alpha1.BootTypeUEFI, SecureBoot: true, MachineType: v1alpha1.MachineTypeQ35},
+			vtpm:        true,
+			observed:    nutanix.VMBootInfo{Type: v1alpha1.BootTypeUEFI, MachineType: v1alpha1.MachineTypeQ35},
+			wantDesired: "UEFI/Q35+secureBoot+vtpm",
+			wantActual:  "UEFI/Q35",
+		},
+		"DeviceOrder": {
+			reason:      "The observed boot order is compared when a desired order is set.",
+			boot:        &v1alpha1.BootConfig{DeviceOrder: []string{v1alpha1.BootDeviceNetwork, v1alpha1.BootDeviceDisk}},
+			observed:    nutanix.VMBootInfo{Type: v1alpha1.BootTypeLegacy, DeviceOrder: []string{v1alpha1.BootDeviceCDROM, v1alpha1.BootDeviceDisk}},
+			wantDesired: "LEGACY/PC:NETWORK>DISK",
+			wantActual:  "LEGACY/PC:CDROM>DISK",
+		},
+		"IgnoredDeviceOrder": {
+			reason:      "The observed boot order is ignored when no order is desired.",
+			observed:    nutanix.VMBootInfo{Type: v1alpha1.BootTypeLegacy, DeviceOrder: []string{v1alpha1.BootDeviceCDROM}},
+			wantDesired: "LEGACY/PC",
+			wantActual:  "LEGACY/PC",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			desired, actual := formatBoot(tc.boot, tc.vtpm, tc.observed)
+			if diff := cmp.Diff(tc.wantDesired, desired); diff != "" {
+				t.Errorf("\n%s\nformatBoot(...): -want desired, +got desired:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantActual, actual); diff != "" {
+				t.Errorf("\n%s\nformatBoot(...): -want actual, +got actual:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
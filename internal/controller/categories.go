@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/apis/v1beta1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// derivedCategories returns the categories the ProviderConfig's category
+// rules derive from the VM's LoB, labels and external facts.
+func derivedCategories(rules *v1beta1.CategoryRules, vm *v1alpha1.VirtualMachine) map[string]string {
+	categories := map[string]string{}
+	if rules == nil {
+		return categories
+	}
+	if rules.LoBCategory != "" && vm.Spec.LoB != "" {
+		categories[rules.LoBCategory] = vm.Spec.LoB
+	}
+	labels := vm.GetLabels()
+	for label, key := range rules.Labels {
+		if v, ok := labels[label]; ok && v != "" {
+			categories[key] = v
+		}
+	}
+	for fact, key := range rules.ExternalFacts {
+		if v, ok := vm.Spec.ExternalFacts[fact]; ok && v != "" {
+			categories[key] = v
+		}
+	}
+	return categories
+}
+
+// applyCategories merges the categories derived by the ProviderConfig's rules
+// into the VM's spec and checks that every category key and value exists in
+// Prism, creating missing values when the rules allow it. Categories set in
+// the spec take precedence over derived ones.
+func (r *VirtualMachineReconciler) applyCategories(ctx context.Context, ntxCli *nutanix.Client, pc *v1beta1.ProviderConfig, vm *v1alpha1.VirtualMachine) (err error) {
+	ctx, span := tracing.Start(ctx, "ApplyCategories")
+	defer func() { tracing.End(span, err) }()
+
+	rules := pc.Spec.CategoryRules
+	categories := derivedCategories(rules, vm)
+	for k, v := range vm.Spec.Categories {
+		categories[k] = v
+	}
+	if len(categories) == 0 {
+		return nil
+	}
+	vm.Spec.Categories = categories
+
+	keys := make([]string, 0, len(categories))
+	for k := range categories {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := categories[k]
+		ok, err := ntxCli.CategoryExists(ctx, k)
+		if err != nil {
+			return fmt.Errorf("cannot check category %s: %w", k, err)
+		}
+		if !ok {
+			return fmt.Errorf("category %s does not exist", k)
+		}
+		ok, err = ntxCli.CategoryValueExists(ctx, k, v)
+		if err != nil {
+			return fmt.Errorf("cannot check category value %s=%s: %w", k, v, err)
+		}
+		if ok {
+			continue
+		}
+		if rules == nil || !rules.CreateMissingValues {
+			return fmt.Errorf("category value %s=%s does not exist", k, v)
+		}
+		if err := ntxCli.EnsureCategoryValue(ctx, k, v); err != nil {
+			return fmt.Errorf("cannot create category value %s=%s: %w", k, v, err)
+		}
+		r.record.Event(vm, event.Normal(reasonCreatedCategoryValue, fmt.Sprintf("Created category value %s=%s", k, v)))
+	}
+	return nil
+}
+
+// updateManagedCategories sets the categories the provider manages on the
+// VM, such as its ownership, protection policy and anti-affinity group
+// categories, that differ on the observed VM, and removes the keys in remove. They are updated on
+// their own rather than through drift correction, so that they take effect
+// whatever the VM's drift policy. observed is updated to match.
+func (r *VirtualMachineReconciler) updateManagedCategories(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine, observed *nutanix.VMInfo, managed map[string]string, remove []string) (err error) {
+	set := map[string]string{}
+	for k, v := range managed {
+		if current, ok := observed.Categories[k]; !ok || current != v {
+			set[k] = v
+		}
+	}
+	var unset []string
+	for _, k := range remove {
+		if _, ok := observed.Categories[k]; ok {
+			unset = append(unset, k)
+		}
+	}
+	if len(set) == 0 && len(unset) == 0 {
+		return nil
+	}
+	ctx, span := tracing.Start(ctx, "UpdateManagedCategories", tracing.AttrVMUUID.String(vm.Status.VMID))
+	defer func() { tracing.End(span, err) }()
+
+	if err := ntxCli.UpdateVMCategories(ctx, vm.Status.VMID, set, unset); err != nil {
+		return fmt.Errorf("cannot update categories: %w", err)
+	}
+	categories := make(map[string]string, len(observed.Categories)+len(set))
+	for k, v := range observed.Categories {
+		categories[k] = v
+	}
+	for k, v := range set {
+		categories[k] = v
+	}
+	for _, k := range unset {
+		delete(categories, k)
+	}
+	observed.Categories = categories
+	return nil
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+func TestUpdateManagedCategories(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		observed map[string]string
+		managed  map[string]string
+		remove   []string
+		want     map[string]string
+	}{
+		"UpToDate": {
+			reason:   "Categories already set in Prism are left alone.",
+			observed: map[string]string{"Environment": "Dev", "Protection": "Gold"},
+			managed:  map[string]string{"Protection": "Gold"},
+			want:     map[string]string{"Environment": "Dev", "Protection": "Gold"},
+		},
+		"Added": {
+			reason:   "Missing and changed managed categories are set, keeping the VM's other categories.",
+			observed: map[string]string{"Environment": "Dev", "Protection": "Silver"},
+			managed:  map[string]string{"Protection": "Gold", ownerCategoryKey: "uid"},
+			want:     map[string]string{"Environment": "Dev", "Protection": "Gold", ownerCategoryKey: "uid"},
+		},
+		"Removed": {
+			reason:   "Keys in remove are removed when the VM has them.",
+			observed: map[string]string{"Environment": "Dev", "Group": "web"},
+			remove:   []string{"Group", "Other"},
+			want:     map[string]string{"Environment": "Dev"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &VirtualMachineReconciler{}
+			ntxCli := nutanix.NewClient("https://prism.example.com:9440", "admin", "secret", false)
+			vm := &v1alpha1.VirtualMachine{Status: v1alpha1.VirtualMachineStatus{VMID: "vm-uuid-1"}}
+			observed := &nutanix.VMInfo{Categories: tc.observed}
+			if err := r.updateManagedCategories(context.Background(), ntxCli, vm, observed, tc.managed, tc.remove); err != nil {
+				t.Fatalf("\n%s\nupdateManagedCategories(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, observed.Categories); diff != "" {
+				t.Errorf("\n%s\nupdateManagedCategories(...): -want observed categories, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
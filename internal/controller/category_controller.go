@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupCategory adds a controller that reconciles Category resources.
+func SetupCategory(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.Category](mgr, o, "Category",
+		func() *v1alpha1.Category { return &v1alpha1.Category{} }, &categoryExternal{})
+}
+
+// SetupCategoryValue adds a controller that reconciles CategoryValue resources.
+func SetupCategoryValue(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.CategoryValue](mgr, o, "CategoryValue",
+		func() *v1alpha1.CategoryValue { return &v1alpha1.CategoryValue{} }, &categoryValueExternal{kube: mgr.GetClient()})
+}
+
+// categoryExternal manages Prism category keys. The external name is the key.
+type categoryExternal struct{}
+
+func (e *categoryExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Category) (externalObservation, error) {
+	observed, err := ntxCli.GetCategory(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.SystemDefined = observed.SystemDefined
+	upToDate := observed.Description == cr.Spec.Description &&
+		(cr.Spec.Cardinality == 0 || observed.Cardinality == cr.Spec.Cardinality)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *categoryExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Category) (string, error) {
+	return cr.Spec.Name, ntxCli.PutCategory(ctx, categoryInfo(cr))
+}
+
+func (e *categoryExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Category) error {
+	info := categoryInfo(cr)
+	info.Name = meta.GetExternalName(cr)
+	return ntxCli.PutCategory(ctx, info)
+}
+
+func (e *categoryExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Category) error {
+	if cr.Status.SystemDefined {
+		return nil
+	}
+	name := meta.GetExternalName(cr)
+	values, err := ntxCli.ListCategoryValues(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(values) > 0 {
+		return fmt.Errorf("category %s still has %d values", name, len(values))
+	}
+	if err := ntxCli.DeleteCategory(ctx, name); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func categoryInfo(cr *v1alpha1.Category) nutanix.CategoryInfo {
+	return nutanix.CategoryInfo{Name: cr.Spec.Name, Description: cr.Spec.Description, Cardinality: cr.Spec.Cardinality}
+}
+
+// categoryValueExternal manages values of Prism category keys. The external
+// name is the value; the key is taken from the spec.
+type categoryValueExternal struct {
+	kube client.Client
+}
+
+func (e *categoryValueExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.CategoryValue) (externalObservation, error) {
+	observed, err := ntxCli.GetCategoryValue(ctx, cr.Spec.Category, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.SystemDefined = observed.SystemDefined
+	return externalObservation{Exists: true, UpToDate: observed.Description == cr.Spec.Description}, nil
+}
+
+func (e *categoryValueExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.CategoryValue) (string, error) {
+	return cr.Spec.Value, ntxCli.PutCategoryValue(ctx, categoryValueInfo(cr, cr.Spec.Value))
+}
+
+func (e *categoryValueExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.CategoryValue) error {
+	return ntxCli.PutCategoryValue(ctx, categoryValueInfo(cr, meta.GetExternalName(cr)))
+}
+
+func (e *categoryValueExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.CategoryValue) error {
+	if cr.Status.SystemDefined {
+		return nil
+	}
+	key, value := cr.Spec.Category, meta.GetExternalName(cr)
+
+	var vms v1alpha1.VirtualMachineList
+	if err := e.kube.List(ctx, &vms); err != nil {
+		return fmt.Errorf("cannot list VirtualMachines: %w", err)
+	}
+	for _, vm := range vms.Items {
+		if vm.Spec.Categories[key] == value {
+			return fmt.Errorf("category value %s=%s is still used by VirtualMachine %s/%s", key, value, vm.Namespace, vm.Name)
+		}
+	}
+	n, err := ntxCli.CountCategoryValueUsage(ctx, key, value)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("category value %s=%s is still attached to %d entities in Prism", key, value, n)
+	}
+	if err := ntxCli.DeleteCategoryValue(ctx, key, value); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func categoryValueInfo(cr *v1alpha1.CategoryValue, value string) nutanix.CategoryValueInfo {
+	return nutanix.CategoryValueInfo{Category: cr.Spec.Category, Value: value, Description: cr.Spec.Description}
+}
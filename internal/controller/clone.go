@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// validateSource checks that spec.source selects exactly one source and is
+// not combined with the other ways of creating the VM.
+func validateSource(vm *v1alpha1.VirtualMachine) error {
+	src := vm.Spec.Source
+	if src == nil {
+		return nil
+	}
+	n := 0
+	for _, set := range []bool{src.VM != "", src.Template != "", src.TemplateRef != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("source must set exactly one of vm, template and templateRef")
+	}
+	if src.Version != "" && src.VM != "" {
+		return fmt.Errorf("source.version is only valid with template or templateRef")
+	}
+	if vm.Spec.RestoreFrom != nil || vm.Spec.ImageName != "" || vm.Spec.ImageUUID != "" || vm.Spec.BootDisk != nil || len(vm.Spec.AdditionalDisks) > 0 {
+		return fmt.Errorf("source cannot be combined with restoreFrom, imageName, imageUuid, bootDisk or additionalDisks; the VM's disks are cloned from the source")
+	}
+	return nil
+}
+
+// cloneVM creates the VM by cloning the VM or deploying the template version
+// selected by spec.source and returns the new VM's UUID. The VM's sizing,
+// CPU and memory settings, NIC, categories and guest customization are
+// applied on top of the source's.
+func (r *VirtualMachineReconciler) cloneVM(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine, guest *nutanix.GuestCustomization) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "CloneVM", tracing.AttrVMName.String(vm.Spec.Name))
+	defer func() { tracing.End(span, err) }()
+
+	src := vm.Spec.Source
+	if src.VM != "" {
+		source, err := findVM(ctx, ntxCli, src.VM)
+		if err != nil {
+			return "", err
+		}
+		if vm.Spec.ClusterUUID != "" && source.ClusterUUID != "" && source.ClusterUUID != vm.Spec.ClusterUUID {
+			return "", fmt.Errorf("source VM %s is on cluster %s, not %s; clones are placed on the source VM's cluster", source.Name, source.ClusterName, vm.Spec.ClusterName)
+		}
+		id, err = ntxCli.CloneVM(ctx, source.UUID, withOwnerCategory(vm), guest)
+		if err != nil {
+			return "", err
+		}
+		r.record.Event(vm, event.Normal(reasonCloned, fmt.Sprintf("Cloned VM %s from VM %s (%s)", vm.Spec.Name, source.Name, source.UUID)))
+		return id, nil
+	}
+
+	var template *nutanix.TemplateInfo
+	if src.TemplateRef != nil {
+		var t v1alpha1.VMTemplate
+		if err := r.Get(ctx, client.ObjectKey{Namespace: vm.Namespace, Name: src.TemplateRef.Name}, &t); err != nil {
+			return "", fmt.Errorf("cannot get VMTemplate %s: %w", src.TemplateRef.Name, err)
+		}
+		if t.Spec.Datacenter != vm.Spec.Datacenter {
+			return "", fmt.Errorf("VMTemplate %s is in datacenter %q, not %q", t.Name, t.Spec.Datacenter, vm.Spec.Datacenter)
+		}
+		uuid := meta.GetExternalName(&t)
+		if uuid == "" {
+			return "", fmt.Errorf("VMTemplate %s has not been created yet", t.Name)
+		}
+		template, err = ntxCli.GetTemplate(ctx, uuid)
+		if err == nil && template == nil {
+			template = &nutanix.TemplateInfo{UUID: uuid, Name: t.Spec.Name}
+			for _, v := range t.Status.Versions {
+				template.Versions = append(template.Versions, nutanix.TemplateVersionInfo{UUID: v.UUID, Name: v.Name, Active: v.Name == t.Status.ActiveVersion})
+			}
+		}
+	} else {
+		template, err = ntxCli.FindTemplate(ctx, src.Template)
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot get template: %w", err)
+	}
+
+	version := template.ActiveVersion()
+	if src.Version != "" {
+		version = nil
+		for i := range template.Versions {
+			if template.Versions[i].Name == src.Version {
+				version = &template.Versions[i]
+			}
+		}
+	}
+	if version == nil {
+		return "", fmt.Errorf("template %s has no version %q", template.Name, src.Version)
+	}
+	id, err = ntxCli.DeployTemplate(ctx, template.UUID, version.UUID, withOwnerCategory(vm), guest)
+	if err != nil {
+		return "", err
+	}
+	r.record.Event(vm, event.Normal(reasonCloned, fmt.Sprintf("Deployed VM %s from template %s version %s", vm.Spec.Name, template.Name, version.Name)))
+	return id, nil
+}
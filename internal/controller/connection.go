@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// Connection detail keys published for a VirtualMachine, in addition to the
+// standard Crossplane username, password and endpoint keys.
+const (
+	connKeyPrimaryIP     = "primary_ip"
+	connKeyIPAddresses   = "ip_addresses"
+	connKeyHostname      = "hostname"
+	connKeyFQDN          = "fqdn"
+	connKeyFloatingIP    = "floating_ip"
+	connKeySSHPublicKey  = "ssh_public_key"
+	connKeySSHPrivateKey = "ssh_private_key"
+)
+
+const (
+	defaultGuestUsername = "nutanix"
+	generatedPasswordLen = 24
+	generatedSSHKeyBits  = 3072
+	passwordCharacters   = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789-_.!"
+)
+
+// validateConnection rejects connection settings the provider cannot honour.
+// Secrets must live in the VM's namespace, and generated credentials need a
+// connection Secret or secret store to be kept in, as they would otherwise be
+// regenerated on every reconcile.
+func (r *VirtualMachineReconciler) validateConnection(vm *v1alpha1.VirtualMachine) error {
+	wref := vm.GetWriteConnectionSecretToReference()
+	if wref != nil && wref.Namespace != vm.Namespace {
+		return fmt.Errorf("writeConnectionSecretToRef must be in the VirtualMachine's namespace %s", vm.Namespace)
+	}
+	gc := vm.Spec.GuestCredentials
+	if gc == nil {
+		return nil
+	}
+	if ref := gc.PasswordSecretRef; ref != nil && ref.Namespace != vm.Namespace {
+		return fmt.Errorf("guestCredentials.passwordSecretRef must be in the VirtualMachine's namespace %s", vm.Namespace)
+	}
+	stored := wref != nil || (r.store != nil && vm.GetPublishConnectionDetailsTo() != nil)
+	if stored {
+		return nil
+	}
+	if gc.PasswordSecretRef == nil {
+		return fmt.Errorf("guestCredentials without passwordSecretRef requires writeConnectionSecretToRef or publishConnectionDetailsTo to keep the generated password")
+	}
+	if gc.GenerateSSHKey && gc.SSHPublicKey == "" {
+		return fmt.Errorf("guestCredentials.generateSshKey requires writeConnectionSecretToRef or publishConnectionDetailsTo to keep the generated key")
+	}
+	return nil
+}
+
+// fetchConnection returns the connection details already published for vm,
+// from its connection Secret or, failing that, its external secret store.
+func (r *VirtualMachineReconciler) fetchConnection(ctx context.Context, vm *v1alpha1.VirtualMachine) (managed.ConnectionDetails, error) {
+	if ref := vm.GetWriteConnectionSecretToReference(); ref != nil {
+		var s corev1.Secret
+		err := r.Get(ctx, client.ObjectKey{Namespace: vm.Namespace, Name: ref.Name}, &s)
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("cannot fetch existing connection details: %w", err)
+		}
+		if len(s.Data) > 0 {
+			return s.Data, nil
+		}
+	}
+	if r.store != nil && vm.GetPublishConnectionDetailsTo() != nil {
+		conn, err := r.store.FetchConnection(ctx, vm)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch existing connection details: %w", err)
+		}
+		return conn, nil
+	}
+	return managed.ConnectionDetails{}, nil
+}
+
+// connectionDetails builds the VM's connection details. Credentials are
+// reused from existing, taken from the referenced Secret or generated.
+// Network details are added when the VM has been observed.
+func (r *VirtualMachineReconciler) connectionDetails(ctx context.Context, vm *v1alpha1.VirtualMachine, existing managed.ConnectionDetails, observed *nutanix.VMInfo) (managed.ConnectionDetails, error) {
+	conn := managed.ConnectionDetails{}
+	if gc := vm.Spec.GuestCredentials; gc != nil {
+		username := gc.Username
+		if username == "" {
+			username = defaultGuestUsername
+		}
+		conn[xpv1.ResourceCredentialsSecretUserKey] = []byte(username)
+
+		switch {
+		case gc.PasswordSecretRef != nil:
+			var s corev1.Secret
+			ref := gc.PasswordSecretRef
+			if err := r.Get(ctx, client.ObjectKey{Namespace: vm.Namespace, Name: ref.Name}, &s); err != nil {
+				return nil, fmt.Errorf("cannot get guest password secret: %w", err)
+			}
+			if len(s.Data[ref.Key]) == 0 {
+				return nil, fmt.Errorf("guest password secret %s has no key %s", ref.Name, ref.Key)
+			}
+			conn[xpv1.ResourceCredentialsSecretPasswordKey] = s.Data[ref.Key]
+		case len(existing[xpv1.ResourceCredentialsSecretPasswordKey]) > 0:
+			conn[xpv1.ResourceCredentialsSecretPasswordKey] = existing[xpv1.ResourceCredentialsSecretPasswordKey]
+		default:
+			pw, err := generatePassword(generatedPasswordLen)
+			if err != nil {
+				return nil, err
+			}
+			conn[xpv1.ResourceCredentialsSecretPasswordKey] = []byte(pw)
+		}
+
+		switch {
+		case gc.SSHPublicKey != "":
+			conn[connKeySSHPublicKey] = []byte(gc.SSHPublicKey)
+		case gc.GenerateSSHKey && len(existing[connKeySSHPrivateKey]) > 0:
+			conn[connKeySSHPublicKey] = existing[connKeySSHPublicKey]
+			conn[connKeySSHPrivateKey] = existing[connKeySSHPrivateKey]
+		case gc.GenerateSSHKey:
+			pub, priv, err := generateSSHKeyPair(generatedSSHKeyBits)
+			if err != nil {
+				return nil, err
+			}
+			conn[connKeySSHPublicKey] = pub
+			conn[connKeySSHPrivateKey] = priv
+		}
+	}
+
+	conn[connKeyHostname] = []byte(vm.Spec.Name)
+	// resolveSubnet replaces a partial SubnetName with the full name of the
+	// subnet it matched, which names the network details file.
+	if vm.Spec.SubnetName != "" {
+		if details, err := readDetailsByName("network", vm.Spec.SubnetName); err == nil {
+			if domain, err := getValue(details, "domain"); err == nil && domain != "" {
+				conn[connKeyFQDN] = []byte(vm.Spec.Name + "." + domain)
+			}
+		}
+	}
+
+	if observed != nil {
+		ips := make([]string, 0, len(observed.NICs))
+		for _, nic := range observed.NICs {
+			if nic.IPAddress != "" {
+				ips = append(ips, nic.IPAddress)
+			}
+		}
+		if len(ips) > 0 {
+			conn[connKeyPrimaryIP] = []byte(ips[0])
+			conn[xpv1.ResourceCredentialsSecretEndpointKey] = []byte(ips[0])
+			conn[connKeyIPAddresses] = []byte(strings.Join(ips, ","))
+		}
+	}
+	// The floating IP is the VM's address outside its VPC, so it replaces
+	// the private address as the endpoint.
+	if fip := vm.Status.FloatingIP; fip != nil && fip.Address != "" {
+		conn[connKeyFloatingIP] = []byte(fip.Address)
+		conn[xpv1.ResourceCredentialsSecretEndpointKey] = []byte(fip.Address)
+	}
+	return conn, nil
+}
+
+// publishConnection builds the VM's connection details and publishes them to
+// its connection Secret and external secret store, if configured.
+func (r *VirtualMachineReconciler) publishConnection(ctx context.Context, vm *v1alpha1.VirtualMachine, observed *nutanix.VMInfo) (managed.ConnectionDetails, error) {
+	if vm.Spec.GuestCredentials == nil && vm.GetWriteConnectionSecretToReference() == nil && vm.GetPublishConnectionDetailsTo() == nil {
+		return nil, nil
+	}
+	existing, err := r.fetchConnection(ctx, vm)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := r.connectionDetails(ctx, vm, existing, observed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.publisher.PublishConnection(ctx, vm, conn); err != nil {
+		return nil, fmt.Errorf("cannot publish connection details: %w", err)
+	}
+	return conn, nil
+}
+
+// guestCustomization returns the guest credentials to configure at create
+// time from the VM's connection details.
+func guestCustomization(vm *v1alpha1.VirtualMachine, conn managed.ConnectionDetails) *nutanix.GuestCustomization {
+	if vm.Spec.GuestCredentials == nil {
+		return nil
+	}
+	return &nutanix.GuestCustomization{
+		Username:         string(conn[xpv1.ResourceCredentialsSecretUserKey]),
+		Password:         string(conn[xpv1.ResourceCredentialsSecretPasswordKey]),
+		SSHAuthorizedKey: string(conn[connKeySSHPublicKey]),
+	}
+}
+
+// generatePassword returns a random password of n characters.
+func generatePassword(n int) (string, error) {
+	max := big.NewInt(int64(len(passwordCharacters)))
+	b := make([]byte, n)
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("cannot generate password: %w", err)
+		}
+		b[i] = passwordCharacters[idx.Int64()]
+	}
+	return string(b), nil
+}
+
+// generateSSHKeyPair returns an RSA key pair as an OpenSSH authorized key and
+// a PEM encoded private key.
+func generateSSHKeyPair(bits int) (authorizedKey, privateKey []byte, err error) {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot generate SSH key: %w", err)
+	}
+	privateKey = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	// Wire format from RFC 4253 section 6.6: string "ssh-rsa", mpint e, mpint n.
+	var wire []byte
+	wire = appendSSHString(wire, []byte("ssh-rsa"))
+	wire = appendSSHString(wire, sshMPInt(big.NewInt(int64(key.PublicKey.E))))
+	wire = appendSSHString(wire, sshMPInt(key.PublicKey.N))
+	authorizedKey = []byte("ssh-rsa " + base64.StdEncoding.EncodeToString(wire))
+	return authorizedKey, privateKey, nil
+}
+
+func appendSSHString(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// sshMPInt encodes a positive integer as an SSH mpint, adding a leading zero
+// byte when the high bit is set.
+func sshMPInt(n *big.Int) []byte {
+	b := n.Bytes()
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return b
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+)
+
+func TestGenerateSSHKeyPair(t *testing.T) {
+	authorizedKey, privateKey, err := generateSSHKeyPair(1024)
+	if err != nil {
+		t.Fatalf("generateSSHKeyPair(...): %v", err)
+	}
+
+	block, _ := pem.Decode(privateKey)
+	if block == nil || block.Type != "RSA PRIVATE KEY" {
+		t.Fatalf("generateSSHKeyPair(...): private key is not a PEM encoded RSA key:\n%s", privateKey)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("generateSSHKeyPair(...): cannot parse private key: %v", err)
+	}
+
+	typ, encoded, ok := strings.Cut(string(authorizedKey), " ")
+	if !ok || typ != "ssh-rsa" {
+		t.Fatalf("generateSSHKeyPair(...): authorized key %q is not an ssh-rsa key", authorizedKey)
+	}
+	wire, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("generateSSHKeyPair(...): cannot decode authorized key: %v", err)
+	}
+	var fields [][]byte
+	for len(wire) > 0 {
+		if len(wire) < 4 || int(binary.BigEndian.Uint32(wire)) > len(wire)-4 {
+			t.Fatalf("generateSSHKeyPair(...): malformed authorized key")
+		}
+		n := binary.BigEndian.Uint32(wire)
+		fields, wire = append(fields, wire[4:4+n]), wire[4+n:]
+	}
+	if len(fields) != 3 {
+		t.Fatalf("generateSSHKeyPair(...): authorized key has %d fields, want 3", len(fields))
+	}
+	if diff := cmp.Diff("ssh-rsa", string(fields[0])); diff != "" {
+		t.Errorf("generateSSHKeyPair(...): -want key type, +got key type:\n%s", diff)
+	}
+	if got := new(big.Int).SetBytes(fields[1]).Int64(); got != int64(key.PublicKey.E) {
+		t.Errorf("generateSSHKeyPair(...): public exponent %d does not match private key's %d", got, key.PublicKey.E)
+	}
+	if got := new(big.Int).SetBytes(fields[2]); got.Cmp(key.PublicKey.N) != 0 {
+		t.Errorf("generateSSHKeyPair(...): modulus does not match private key")
+	}
+}
+
+func TestSSHMPInt(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		n      *big.Int
+		want   []byte
+	}{
+		"Zero": {
+			reason: "Zero is encoded as an empty string.",
+			n:      big.NewInt(0),
+			want:   []byte{},
+		},
+		"HighBitClear": {
+			reason: "Integers without the high bit set are encoded as is.",
+			n:      big.NewInt(65537),
+			want:   []byte{0x01, 0x00, 0x01},
+		},
+		"HighBitSet": {
+			reason: "A zero byte is prepended when the high bit is set, so the integer stays positive.",
+			n:      big.NewInt(0x80),
+			want:   []byte{0x00, 0x80},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := sshMPInt(tc.n); !bytes.Equal(tc.want, got) {
+				t.Errorf("\n%s\nsshMPInt(%s): want %x, got %x", tc.reason, tc.n, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	pw, err := generatePassword(generatedPasswordLen)
+	if err != nil {
+		t.Fatalf("generatePassword(...): %v", err)
+	}
+	if len(pw) != generatedPasswordLen {
+		t.Errorf("generatePassword(%d): got %d characters", generatedPasswordLen, len(pw))
+	}
+	for _, c := range pw {
+		if !strings.ContainsRune(passwordCharacters, c) {
+			t.Errorf("generatePassword(...): %q is not an allowed character", c)
+		}
+	}
+}
+
+func TestValidateConnection(t *testing.T) {
+	conn := &xpv1.SecretReference{Namespace: "team-a", Name: "vm-conn"}
+	password := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "team-a", Name: "vm-password"}, Key: "password"}
+
+	cases := map[string]struct {
+		reason string
+		spec   v1alpha1.VirtualMachineSpec
+		want   string
+	}{
+		"NoConnection": {
+			reason: "VMs without connection settings are accepted.",
+		},
+		"GeneratedWithSecret": {
+			reason: "Generated credentials are kept in the connection Secret.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestCredentials:                 &v1alpha1.GuestCredentials{GenerateSSHKey: true},
+				WriteConnectionSecretToReference: conn,
+			},
+		},
+		"SecretInOtherNamespace": {
+			reason: "The connection Secret must be in the VM's namespace.",
+			spec: v1alpha1.VirtualMachineSpec{
+				WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: "crossplane-system", Name: "vm-conn"},
+			},
+			want: "writeConnectionSecretToRef must be in the VirtualMachine's namespace team-a",
+		},
+		"PasswordInOtherNamespace": {
+			reason: "The password Secret must be in the VM's namespace.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestCredentials: &v1alpha1.GuestCredentials{PasswordSecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Namespace: "team-b", Name: "vm-password"}, Key: "password",
+				}},
+				WriteConnectionSecretToReference: conn,
+			},
+			want: "guestCredentials.passwordSecretRef must be in the VirtualMachine's namespace team-a",
+		},
+		"GeneratedPasswordNotStored": {
+			reason: "A generated password without a connection Secret would change on every reconcile.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestCredentials: &v1alpha1.GuestCredentials{},
+			},
+			want: "guestCredentials without passwordSecretRef requires writeConnectionSecretToRef or publishConnectionDetailsTo to keep the generated password",
+		},
+		"GeneratedKeyNotStored": {
+			reason: "A generated SSH key without a connection Secret would change on every reconcile.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestCredentials: &v1alpha1.GuestCredentials{PasswordSecretRef: password, GenerateSSHKey: true},
+			},
+			want: "guestCredentials.generateSshKey requires writeConnectionSecretToRef or publishConnectionDetailsTo to keep the generated key",
+		},
+		"ProvidedCredentials": {
+			reason: "Credentials that are not generated need nowhere to be kept.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestCredentials: &v1alpha1.GuestCredentials{PasswordSecretRef: password, SSHPublicKey: "ssh-ed25519 AAAA"},
+			},
+		},
+		"StoreDisabled": {
+			reason: "publishConnectionDetailsTo only keeps credentials when external secret stores are enabled.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestCredentials:           &v1alpha1.GuestCredentials{},
+				PublishConnectionDetailsTo: &xpv1.PublishConnectionDetailsTo{Name: "vm-conn"},
+			},
+			want: "guestCredentials without passwordSecretRef requires writeConnectionSecretToRef or publishConnectionDetailsTo to keep the generated password",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &VirtualMachineReconciler{}
+			vm := &v1alpha1.VirtualMachine{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "vm"}, Spec: tc.spec}
+			err := r.validateConnection(vm)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidateConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
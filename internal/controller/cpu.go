@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// validateCPU checks the VM's CPU topology against numVcpus and its vNUMA
+// and memory settings against each other.
+func validateCPU(vm *v1alpha1.VirtualMachine) error {
+	cpu, memory := vm.Spec.CPU, vm.Spec.Memory
+	if cpu != nil && vm.Spec.NumVCPUs == 0 {
+		return fmt.Errorf("cpu requires numVcpus")
+	}
+	if cpu == nil {
+		cpu = &v1alpha1.CPUConfig{}
+	}
+	if memory == nil {
+		memory = &v1alpha1.MemoryConfig{}
+	}
+	sockets, cores, _ := nutanix.CPUTopology(vm.Spec.NumVCPUs, cpu)
+	if sockets*cores != vm.Spec.NumVCPUs {
+		return fmt.Errorf("cpu: %d sockets of %d cores do not add up to numVcpus %d", sockets, cores, vm.Spec.NumVCPUs)
+	}
+	if n := cpu.NUMANodes; n > 0 {
+		if sockets%n != 0 || vm.Spec.MemorySizeMiB%n != 0 {
+			return fmt.Errorf("cpu: %d sockets and %d MiB of memory cannot be split evenly across %d vNUMA nodes", sockets, vm.Spec.MemorySizeMiB, n)
+		}
+		if memory.Overcommit || memory.HotPlug {
+			return fmt.Errorf("memory overcommit and hot-plug are not supported with vNUMA")
+		}
+	}
+	if memory.Overcommit && cpu.Passthrough {
+		return fmt.Errorf("memory overcommit is not supported with CPU passthrough")
+	}
+	return nil
+}
+
+// formatDesiredCPU renders the CPU settings of spec as
+// "socketsxcoresxthreads" followed by ",numa=N", "+passthrough" and
+// "+nestedVirtualization" when set.
+func formatDesiredCPU(numVCPUs int, cpu *v1alpha1.CPUConfig) string {
+	sockets, cores, threads := nutanix.CPUTopology(numVCPUs, cpu)
+	return formatCPU(nutanix.VMCPUInfo{
+		Sockets: sockets, CoresPerSocket: cores, ThreadsPerCore: threads, NUMANodes: cpu.NUMANodes,
+		Passthrough: cpu.Passthrough, HardwareVirtualization: cpu.HardwareVirtualization,
+	})
+}
+
+// formatCPU renders observed CPU settings the same way as formatDesiredCPU.
+func formatCPU(cpu nutanix.VMCPUInfo) string {
+	s := fmt.Sprintf("%dx%dx%d", cpu.Sockets, cpu.CoresPerSocket, cpu.ThreadsPerCore)
+	if cpu.NUMANodes > 0 {
+		s += fmt.Sprintf(",numa=%d", cpu.NUMANodes)
+	}
+	if cpu.Passthrough {
+		s += "+passthrough"
+	}
+	if cpu.HardwareVirtualization {
+		s += "+nestedVirtualization"
+	}
+	return s
+}
+
+// formatMemory renders memory settings as "overcommit=BOOL,hotPlug=BOOL".
+func formatMemory(overcommit, hotPlug bool) string {
+	return fmt.Sprintf("overcommit=%t,hotPlug=%t", overcommit, hotPlug)
+}
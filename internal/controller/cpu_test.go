@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+)
+
+func TestValidateCPU(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		spec   v1alpha1.VirtualMachineSpec
+		want   string
+	}{
+		"NoCPUConfig": {
+			reason: "Without a CPU config every vCPU is its own socket.",
+			spec:   v1alpha1.VirtualMachineSpec{NumVCPUs: 4, MemorySizeMiB: 8192},
+		},
+		"CoresPerSocket": {
+			reason: "Sockets default to numVcpus divided by the cores per socket.",
+			spec:   v1alpha1.VirtualMachineSpec{NumVCPUs: 8, CPU: &v1alpha1.CPUConfig{CoresPerSocket: 4}},
+		},
+		"Topology": {
+			reason: "Sockets times cores per socket must equal numVcpus.",
+			spec:   v1alpha1.VirtualMachineSpec{NumVCPUs: 8, CPU: &v1alpha1.CPUConfig{Sockets: 2, CoresPerSocket: 2}},
+			want:   "cpu: 2 sockets of 2 cores do not add up to numVcpus 8",
+		},
+		"UnevenCores": {
+			reason: "numVcpus must be a multiple of the cores per socket.",
+			spec:   v1alpha1.VirtualMachineSpec{NumVCPUs: 6, CPU: &v1alpha1.CPUConfig{CoresPerSocket: 4}},
+			want:   "cpu: 1 sockets of 4 cores do not add up to numVcpus 6",
+		},
+		"RequiresNumVCPUs": {
+			reason: "A CPU config needs numVcpus to check the topology against.",
+			spec:   v1alpha1.VirtualMachineSpec{CPU: &v1alpha1.CPUConfig{Sockets: 2}},
+			want:   "cpu requires numVcpus",
+		},
+		"NUMA": {
+			reason: "Sockets and memory split evenly across vNUMA nodes are accepted.",
+			spec: v1alpha1.VirtualMachineSpec{
+				NumVCPUs: 8, MemorySizeMiB: 16384,
+				CPU: &v1alpha1.CPUConfig{Sockets: 4, CoresPerSocket: 2, NUMANodes: 2},
+			},
+		},
+		"UnevenNUMA": {
+			reason: "Sockets must split evenly across vNUMA nodes.",
+			spec: v1alpha1.VirtualMachineSpec{
+				NumVCPUs: 3, MemorySizeMiB: 3072,
+				CPU: &v1alpha1.CPUConfig{NUMANodes: 2},
+			},
+			want: "cpu: 3 sockets and 3072 MiB of memory cannot be split evenly across 2 vNUMA nodes",
+		},
+		"NUMAWithHotPlug": {
+			reason: "vNUMA excludes memory hot-plug.",
+			spec: v1alpha1.VirtualMachineSpec{
+				NumVCPUs: 2, MemorySizeMiB: 4096,
+				CPU:    &v1alpha1.CPUConfig{NUMANodes: 2},
+				Memory: &v1alpha1.MemoryConfig{HotPlug: true},
+			},
+			want: "memory overcommit and hot-plug are not supported with vNUMA",
+		},
+		"OvercommitWithPassthrough": {
+			reason: "Memory overcommit excludes CPU passthrough.",
+			spec: v1alpha1.VirtualMachineSpec{
+				NumVCPUs: 2,
+				CPU:      &v1alpha1.CPUConfig{Passthrough: true},
+				Memory:   &v1alpha1.MemoryConfig{Overcommit: true},
+			},
+			want: "memory overcommit is not supported with CPU passthrough",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateCPU(&v1alpha1.VirtualMachine{Spec: tc.spec})
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidateCPU(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFormatDesiredCPU(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		numVCPUs int
+		cpu      *v1alpha1.CPUConfig
+		want     string
+	}{
+		"Defaults": {
+			reason:   "Unset cores and threads default to one.",
+			numVCPUs: 4,
+			cpu:      &v1alpha1.CPUConfig{},
+			want:     "4x1x1",
+		},
+		"Features": {
+			reason:   "vNUMA, passthrough and nested virtualization are appended.",
+			numVCPUs: 8,
+			cpu:      &v1alpha1.CPUConfig{CoresPerSocket: 4, ThreadsPerCore: 2, NUMANodes: 2, Passthrough: true, HardwareVirtualization: true},
+			want:     "2x4x2,numa=2+passthrough+nestedVirtualization",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, formatDesiredCPU(tc.numVCPUs, tc.cpu)); diff != "" {
+				t.Errorf("\n%s\nformatDesiredCPU(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// maxDeviceIndex is the highest device index of the buses with a limited
+// number of devices.
+var maxDeviceIndex = map[string]int{
+	v1alpha1.DiskBusIDE:  3,
+	v1alpha1.DiskBusSATA: 5,
+}
+
+const bytesPerGiB = 1 << 30
+
+// vmDevice is a boot disk, disk or CD-ROM of a VM being validated.
+type vmDevice struct {
+	name          string
+	deviceType    string
+	bus           string
+	index         int
+	sizeGb        int
+	imageUUID     string
+	containerRef  *xpv1.Reference
+	containerName string
+	containerUUID *string
+	flashMode     bool
+}
+
+// resolveDisks validates the VM's boot disk, disks and CD-ROMs against each
+// other, their images and the VM's cluster, and resolves storage container
+// names to UUIDs.
+func (r *VirtualMachineReconciler) resolveDisks(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	ctx, span := tracing.Start(ctx, "ResolveDisks", tracing.AttrCluster.String(vm.Spec.ClusterName))
+	defer func() { tracing.End(span, err) }()
+
+	var devices []vmDevice
+	if boot := vm.Spec.BootDisk; boot != nil {
+		if vm.Status.VMID == "" && vm.Spec.RestoreFrom == nil && vm.Spec.ImageUUID == "" {
+			return fmt.Errorf("bootDisk requires imageName or imageUuid")
+		}
+		devices = append(devices, vmDevice{
+			name: "bootDisk", deviceType: v1alpha1.DeviceTypeDisk, bus: nutanix.DiskBus(boot.Bus, v1alpha1.DeviceTypeDisk),
+			sizeGb: boot.SizeGb, imageUUID: vm.Spec.ImageUUID,
+			containerRef: boot.StorageContainerRef, containerName: boot.StorageContainerName,
+			containerUUID: &boot.StorageContainerUUID, flashMode: boot.FlashMode,
+		})
+	} else if vm.Spec.ImageUUID != "" {
+		// The implicit boot disk is cloned from the image at its size.
+		devices = append(devices, vmDevice{name: "bootDisk", deviceType: v1alpha1.DeviceTypeDisk, bus: v1alpha1.DiskBusSCSI, imageUUID: vm.Spec.ImageUUID})
+	}
+	for i := range vm.Spec.AdditionalDisks {
+		d := &vm.Spec.AdditionalDisks[i]
+		deviceType := d.DeviceType
+		if deviceType == "" {
+			deviceType = v1alpha1.DeviceTypeDisk
+		}
+		devices = append(devices, vmDevice{
+			name: fmt.Sprintf("additionalDisks[%d]", i), deviceType: deviceType, bus: nutanix.DiskBus(d.Bus, deviceType),
+			index: d.DeviceIndex, sizeGb: d.SizeGb, imageUUID: d.ImageUUID,
+			containerRef: d.StorageContainerRef, containerName: d.StorageContainerName,
+			containerUUID: &d.StorageContainerUUID, flashMode: d.FlashMode,
+		})
+	}
+
+	slots := map[string]string{}
+	needImages, needContainers, needFlash := false, false, false
+	for _, d := range devices {
+		if max, ok := maxDeviceIndex[d.bus]; d.index < 0 || ok && d.index > max {
+			return fmt.Errorf("%s: device index %d is out of range for bus %s", d.name, d.index, d.bus)
+		}
+		slot := fmt.Sprintf("%s.%d", d.bus, d.index)
+		if other, ok := slots[slot]; ok {
+			return fmt.Errorf("%s: %s is already used by %s", d.name, slot, other)
+		}
+		slots[slot] = d.name
+
+		if d.deviceType == v1alpha1.DeviceTypeCDROM {
+			if d.bus != v1alpha1.DiskBusIDE && d.bus != v1alpha1.DiskBusSATA {
+				return fmt.Errorf("%s: CD-ROMs must use the IDE or SATA bus", d.name)
+			}
+			if d.sizeGb != 0 || d.containerRef != nil || d.containerName != "" || *d.containerUUID != "" || d.flashMode {
+				return fmt.Errorf("%s: CD-ROMs do not support sizeGb, storage containers or flashMode", d.name)
+			}
+		} else if d.sizeGb <= 0 && d.imageUUID == "" {
+			return fmt.Errorf("%s: sizeGb is required for disks without an image", d.name)
+		}
+		needImages = needImages || d.imageUUID != ""
+		if d.containerRef != nil && d.containerName != "" {
+			return fmt.Errorf("%s: set only one of storageContainerRef and storageContainerName", d.name)
+		}
+		needContainers = needContainers || d.containerRef != nil || d.containerName != "" || (d.containerUUID != nil && *d.containerUUID != "")
+		needFlash = needFlash || d.flashMode
+	}
+
+	if needImages {
+		if err := validateDiskImages(ctx, ntxCli, devices); err != nil {
+			return err
+		}
+	}
+	if (needContainers || needFlash) && vm.Spec.ClusterUUID == "" {
+		return fmt.Errorf("storage containers and flashMode require clusterName or clusterUuid")
+	}
+	if needContainers {
+		if err := r.resolveStorageContainerRefs(ctx, devices); err != nil {
+			return err
+		}
+		if err := resolveStorageContainers(ctx, ntxCli, vm.Spec.ClusterUUID, devices); err != nil {
+			return err
+		}
+	}
+	if needFlash {
+		ok, err := ntxCli.ClusterSupportsFlashMode(ctx, vm.Spec.ClusterUUID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("cluster %s has no hybrid storage and does not support flashMode", vm.Spec.ClusterName)
+		}
+	}
+	return nil
+}
+
+// validateDiskImages checks that disks are cloned from disk images no
+// larger than them and that CD-ROMs mount ISO images.
+func validateDiskImages(ctx context.Context, ntxCli *nutanix.Client, devices []vmDevice) error {
+	images, err := ntxCli.ListImages(ctx)
+	if err != nil {
+		return err
+	}
+	byUUID := make(map[string]nutanix.ImageInfo, len(images))
+	for _, img := range images {
+		byUUID[img.UUID] = img
+	}
+	for _, d := range devices {
+		if d.imageUUID == "" {
+			continue
+		}
+		img, ok := byUUID[d.imageUUID]
+		if !ok {
+			return fmt.Errorf("%s: image %s not found", d.name, d.imageUUID)
+		}
+		if d.deviceType == v1alpha1.DeviceTypeCDROM {
+			if img.ImageType != "" && img.ImageType != v1alpha1.ImageTypeISO {
+				return fmt.Errorf("%s: CD-ROM image %s is not an ISO image", d.name, img.Name)
+			}
+			continue
+		}
+		if img.ImageType == v1alpha1.ImageTypeISO {
+			return fmt.Errorf("%s: ISO image %s can only be mounted on a CD-ROM", d.name, img.Name)
+		}
+		if d.sizeGb > 0 && int64(d.sizeGb)*bytesPerGiB < img.SizeBytes {
+			return fmt.Errorf("%s: sizeGb %d is smaller than image %s (%d bytes)", d.name, d.sizeGb, img.Name, img.SizeBytes)
+		}
+	}
+	return nil
+}
+
+// resolveStorageContainerRefs resolves the StorageContainer references of
+// devices to UUIDs.
+func (r *VirtualMachineReconciler) resolveStorageContainerRefs(ctx context.Context, devices []vmDevice) error {
+	for _, d := range devices {
+		if d.containerRef == nil {
+			continue
+		}
+		var sc v1alpha1.StorageContainer
+		uuid, err := externalNameOf(ctx, r.Client, "StorageContainer", d.containerRef.Name, &sc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", d.name, err)
+		}
+		if *d.containerUUID != "" && *d.containerUUID != uuid {
+			return fmt.Errorf("%s: storageContainerUuid %s does not match StorageContainer %s", d.name, *d.containerUUID, d.containerRef.Name)
+		}
+		*d.containerUUID = uuid
+	}
+	return nil
+}
+
+// resolveStorageContainers resolves the storage container names of devices
+// to UUIDs and checks that all containers are on the cluster clusterUUID.
+func resolveStorageContainers(ctx context.Context, ntxCli *nutanix.Client, clusterUUID string, devices []vmDevice) error {
+	containers, err := ntxCli.ListStorageContainers(ctx, clusterUUID)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]string, len(containers))
+	onCluster := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		byName[c.Name] = c.UUID
+		onCluster[c.UUID] = true
+	}
+	for _, d := range devices {
+		if d.containerUUID == nil {
+			continue
+		}
+		if d.containerName != "" {
+			uuid, ok := byName[d.containerName]
+			if !ok {
+				return fmt.Errorf("%s: storage container %s not found on the VM's cluster", d.name, d.containerName)
+			}
+			if *d.containerUUID != "" && *d.containerUUID != uuid {
+				return fmt.Errorf("%s: storageContainerUuid %s does not match storage container %s", d.name, *d.containerUUID, d.containerName)
+			}
+			*d.containerUUID = uuid
+			continue
+		}
+		if *d.containerUUID != "" && !onCluster[*d.containerUUID] {
+			return fmt.Errorf("%s: storage container %s is not on the VM's cluster", d.name, *d.containerUUID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+func TestResolveDisks(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		spec   v1alpha1.VirtualMachineSpec
+		want   string
+	}{
+		"Valid": {
+			reason: "Disks and CD-ROMs in distinct slots of suitable buses are accepted.",
+			spec: v1alpha1.VirtualMachineSpec{
+				ImageUUID: "img-uuid-1",
+				AdditionalDisks: []v1alpha1.DiskSpec{
+					{DeviceIndex: 1, SizeGb: 50},
+					{DeviceIndex: 0, SizeGb: 10, Bus: v1alpha1.DiskBusPCI},
+					{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeCDROM},
+					{DeviceIndex: 5, DeviceType: v1alpha1.DeviceTypeCDROM, Bus: v1alpha1.DiskBusSATA},
+				},
+			},
+		},
+		"BootDiskSlot": {
+			reason: "The implicit boot disk occupies SCSI.0.",
+			spec: v1alpha1.VirtualMachineSpec{
+				ImageUUID:       "img-uuid-1",
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 0, SizeGb: 50}},
+			},
+			want: "additionalDisks[0]: SCSI.0 is already used by bootDisk",
+		},
+		"DuplicateSlot": {
+			reason: "Two devices cannot share a bus and index.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{
+					{DeviceIndex: 1, DeviceType: v1alpha1.DeviceTypeCDROM},
+					{DeviceIndex: 1, SizeGb: 10, Bus: v1alpha1.DiskBusIDE},
+				},
+			},
+			want: "additionalDisks[1]: IDE.1 is already used by additionalDisks[0]",
+		},
+		"IDEIndexOutOfRange": {
+			reason: "The IDE bus has device indexes 0 to 3.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 4, DeviceType: v1alpha1.DeviceTypeCDROM}},
+			},
+			want: "additionalDisks[0]: device index 4 is out of range for bus IDE",
+		},
+		"SATAIndexOutOfRange": {
+			reason: "The SATA bus has device indexes 0 to 5.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 6, SizeGb: 10, Bus: v1alpha1.DiskBusSATA}},
+			},
+			want: "additionalDisks[0]: device index 6 is out of range for bus SATA",
+		},
+		"NegativeIndex": {
+			reason: "Device indexes cannot be negative.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: -1, SizeGb: 10}},
+			},
+			want: "additionalDisks[0]: device index -1 is out of range for bus SCSI",
+		},
+		"CDROMOnSCSI": {
+			reason: "CD-ROMs can only use the IDE or SATA bus.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 1, DeviceType: v1alpha1.DeviceTypeCDROM, Bus: v1alpha1.DiskBusSCSI}},
+			},
+			want: "additionalDisks[0]: CD-ROMs must use the IDE or SATA bus",
+		},
+		"CDROMWithSize": {
+			reason: "CD-ROMs have no size.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeCDROM, SizeGb: 1}},
+			},
+			want: "additionalDisks[0]: CD-ROMs do not support sizeGb, storage containers or flashMode",
+		},
+		"DiskWithoutSize": {
+			reason: "Empty disks need a size.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 1}},
+			},
+			want: "additionalDisks[0]: sizeGb is required for disks without an image",
+		},
+		"UnknownBootImage": {
+			reason: "The implicit boot disk is checked against its image.",
+			spec: v1alpha1.VirtualMachineSpec{
+				ImageUUID: "img-uuid-missing",
+			},
+			want: "bootDisk: image img-uuid-missing not found",
+		},
+		"BootDiskWithoutImage": {
+			reason: "A new VM's boot disk is cloned from its image.",
+			spec: v1alpha1.VirtualMachineSpec{
+				BootDisk: &v1alpha1.BootDiskSpec{SizeGb: 40},
+			},
+			want: "bootDisk requires imageName or imageUuid",
+		},
+		"BothContainerRefAndName": {
+			reason: "A disk's storage container is set through one field only.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{{
+					DeviceIndex: 1, SizeGb: 10,
+					StorageContainerRef: &xpv1.Reference{Name: "fast"}, StorageContainerName: "fast",
+				}},
+			},
+			want: "additionalDisks[0]: set only one of storageContainerRef and storageContainerName",
+		},
+		"FlashModeWithoutCluster": {
+			reason: "Flash mode is checked against the VM's cluster.",
+			spec: v1alpha1.VirtualMachineSpec{
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 1, SizeGb: 10, FlashMode: true}},
+			},
+			want: "storage containers and flashMode require clusterName or clusterUuid",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &VirtualMachineReconciler{}
+			ntxCli := nutanix.NewClient("https://prism.example.com:9440", "admin", "secret", false)
+			err := r.resolveDisks(context.Background(), ntxCli, &v1alpha1.VirtualMachine{Spec: tc.spec})
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nresolveDisks(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// computeDrift compares the desired VM spec with the state observed in Prism
+// and returns every field that differs, skipping the fields in ignore.
+func computeDrift(spec v1alpha1.VirtualMachineSpec, observed *nutanix.VMInfo, ignore []string) []v1alpha1.FieldDrift {
+	skip := make(map[string]bool, len(ignore))
+	for _, f := range ignore {
+		skip[f] = true
+	}
+
+	var drift []v1alpha1.FieldDrift
+	check := func(field, desired, actual string) {
+		if skip[field] || desired == actual {
+			return
+		}
+		drift = append(drift, v1alpha1.FieldDrift{Field: field, Desired: desired, Observed: actual})
+	}
+
+	// Clones keep the source's sizing when it is unset. Clones and restored
+	// VMs keep the disks of their source or recovery point, which the spec
+	// does not list.
+	if spec.Source == nil || spec.NumVCPUs != 0 {
+		check(v1alpha1.DriftFieldNumVCPUs, fmt.Sprint(spec.NumVCPUs), fmt.Sprint(observed.NumVCPUs))
+	}
+	if spec.Source == nil || spec.MemorySizeMiB != 0 {
+		check(v1alpha1.DriftFieldMemorySizeMiB, fmt.Sprint(spec.MemorySizeMiB), fmt.Sprint(observed.MemorySizeMiB))
+	}
+	if spec.Source == nil && spec.RestoreFrom == nil {
+		check(v1alpha1.DriftFieldDisks, formatDesiredDisks(spec.BootDisk, spec.AdditionalDisks), formatObservedDisks(spec.BootDisk, observed.Disks))
+	}
+	if spec.SubnetUUID != "" {
+		subnets := make([]string, 0, len(observed.NICs))
+		for _, nic := range observed.NICs {
+			subnets = append(subnets, nic.SubnetUUID)
+		}
+		sort.Strings(subnets)
+		check(v1alpha1.DriftFieldNICs, spec.SubnetUUID, strings.Join(subnets, ","))
+	}
+	check(v1alpha1.DriftFieldCategories, formatCategories(spec.Categories), formatCategories(observed.Categories))
+	if spec.PowerState != "" {
+		check(v1alpha1.DriftFieldPowerState, spec.PowerState, observed.PowerState)
+	}
+	if spec.Boot != nil || spec.VTPM {
+		desired, actual := formatBoot(spec.Boot, spec.VTPM, observed.Boot)
+		check(v1alpha1.DriftFieldBoot, desired, actual)
+	}
+	if spec.CPU != nil {
+		check(v1alpha1.DriftFieldCPU, formatDesiredCPU(spec.NumVCPUs, spec.CPU), formatCPU(observed.CPU))
+	}
+	if spec.Memory != nil {
+		check(v1alpha1.DriftFieldMemory, formatMemory(spec.Memory.Overcommit, spec.Memory.HotPlug), formatMemory(observed.Memory.Overcommit, observed.Memory.HotPlug))
+	}
+	return drift
+}
+
+// keepObserved returns spec with each of fields set to its observed value,
+// so that an update correcting other fields leaves them as they are in Prism.
+func keepObserved(spec v1alpha1.VirtualMachineSpec, observed *nutanix.VMInfo, fields []string) v1alpha1.VirtualMachineSpec {
+	for _, f := range fields {
+		switch f {
+		case v1alpha1.DriftFieldNumVCPUs:
+			spec.NumVCPUs = observed.NumVCPUs
+		case v1alpha1.DriftFieldMemorySizeMiB:
+			spec.MemorySizeMiB = observed.MemorySizeMiB
+		case v1alpha1.DriftFieldDisks:
+			if spec.BootDisk != nil {
+				boot := *spec.BootDisk
+				for _, d := range observed.Disks {
+					if isBootDisk(spec.BootDisk, d) {
+						boot.SizeGb = d.SizeGb
+					}
+				}
+				spec.BootDisk = &boot
+			}
+			spec.AdditionalDisks = observedAdditionalDisks(spec.BootDisk, observed.Disks)
+		case v1alpha1.DriftFieldNICs:
+			spec.SubnetUUID = ""
+			if len(observed.NICs) > 0 {
+				spec.SubnetUUID = observed.NICs[0].SubnetUUID
+			}
+		case v1alpha1.DriftFieldCategories:
+			spec.Categories = make(map[string]string, len(observed.Categories))
+			for k, v := range observed.Categories {
+				spec.Categories[k] = v
+			}
+		case v1alpha1.DriftFieldPowerState:
+			spec.PowerState = observed.PowerState
+		case v1alpha1.DriftFieldBoot:
+			spec.Boot = &v1alpha1.BootConfig{
+				Type:        observed.Boot.Type,
+				SecureBoot:  observed.Boot.SecureBoot,
+				MachineType: observed.Boot.MachineType,
+				DeviceOrder: observed.Boot.DeviceOrder,
+			}
+			spec.VTPM = observed.Boot.VTPM
+		case v1alpha1.DriftFieldCPU:
+			spec.CPU = &v1alpha1.CPUConfig{
+				Sockets:                observed.CPU.Sockets,
+				CoresPerSocket:         observed.CPU.CoresPerSocket,
+				ThreadsPerCore:         observed.CPU.ThreadsPerCore,
+				NUMANodes:              observed.CPU.NUMANodes,
+				Passthrough:            observed.CPU.Passthrough,
+				HardwareVirtualization: observed.CPU.HardwareVirtualization,
+			}
+		case v1alpha1.DriftFieldMemory:
+			spec.Memory = &v1alpha1.MemoryConfig{Overcommit: observed.Memory.Overcommit, HotPlug: observed.Memory.HotPlug}
+		}
+	}
+	return spec
+}
+
+// formatDesiredDisks renders additional disks as "BUS.index:sizeGiB" (or
+// "BUS.index:CDROM") entries ordered by bus and device index, preceded by the
+// boot disk size when it is set explicitly.
+func formatDesiredDisks(boot *v1alpha1.BootDiskSpec, disks []v1alpha1.DiskSpec) string {
+	parts := make([]string, 0, len(disks)+1)
+	for _, d := range disks {
+		parts = append(parts, formatDisk(d.DeviceType, d.Bus, d.DeviceIndex, d.SizeGb))
+	}
+	sort.Strings(parts)
+	if boot != nil && boot.SizeGb > 0 {
+		parts = append([]string{fmt.Sprintf("boot:%dGiB", boot.SizeGb)}, parts...)
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatObservedDisks renders observed disks the same way as
+// formatDesiredDisks. The boot disk, at device index 0 of the boot bus, is
+// only included when its size is set explicitly.
+func formatObservedDisks(boot *v1alpha1.BootDiskSpec, disks []nutanix.VMDiskInfo) string {
+	parts := make([]string, 0, len(disks))
+	bootSize := ""
+	for _, d := range disks {
+		if isBootDisk(boot, d) {
+			if boot != nil && boot.SizeGb > 0 {
+				bootSize = fmt.Sprintf("boot:%dGiB", d.SizeGb)
+			}
+			continue
+		}
+		parts = append(parts, formatDisk(d.DeviceType, d.Bus, d.DeviceIndex, d.SizeGb))
+	}
+	sort.Strings(parts)
+	if bootSize != "" {
+		parts = append([]string{bootSize}, parts...)
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatDisk renders a disk as "BUS.index:sizeGiB" or a CD-ROM as
+// "BUS.index:CDROM".
+func formatDisk(deviceType, bus string, index, sizeGb int) string {
+	if deviceType == v1alpha1.DeviceTypeCDROM {
+		return fmt.Sprintf("%s.%d:%s", nutanix.DiskBus(bus, deviceType), index, v1alpha1.DeviceTypeCDROM)
+	}
+	return fmt.Sprintf("%s.%d:%dGiB", nutanix.DiskBus(bus, deviceType), index, sizeGb)
+}
+
+// isBootDisk reports whether the observed disk d is the boot disk described
+// by boot, i.e. the disk at device index 0 of its bus.
+func isBootDisk(boot *v1alpha1.BootDiskSpec, d nutanix.VMDiskInfo) bool {
+	bus := v1alpha1.DiskBusSCSI
+	if boot != nil && boot.Bus != "" {
+		bus = boot.Bus
+	}
+	return d.DeviceIndex == 0 && d.DeviceType != v1alpha1.DeviceTypeCDROM && nutanix.DiskBus(d.Bus, d.DeviceType) == bus
+}
+
+// formatCategories renders categories as sorted "key=value" pairs.
+func formatCategories(categories map[string]string) string {
+	parts := make([]string, 0, len(categories))
+	for k, v := range categories {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// driftFields returns the names of the drifted fields.
+func driftFields(drift []v1alpha1.FieldDrift) []string {
+	fields := make([]string, 0, len(drift))
+	for _, d := range drift {
+		fields = append(fields, d.Field)
+	}
+	return fields
+}
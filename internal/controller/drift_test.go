@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+func TestComputeDrift(t *testing.T) {
+	spec := v1alpha1.VirtualMachineSpec{
+		NumVCPUs:      2,
+		MemorySizeMiB: 4096,
+		SubnetUUID:    "subnet-1",
+		Categories:    map[string]string{"Environment": "Production"},
+		PowerState:    "ON",
+	}
+	observed := func(mutate func(*nutanix.VMInfo)) *nutanix.VMInfo {
+		vm := &nutanix.VMInfo{
+			NumVCPUs:      2,
+			MemorySizeMiB: 4096,
+			Disks:         []nutanix.VMDiskInfo{{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 20}},
+			NICs:          []nutanix.VMNICInfo{{SubnetUUID: "subnet-1"}},
+			Categories:    map[string]string{"Environment": "Production"},
+			PowerState:    "ON",
+		}
+		if mutate != nil {
+			mutate(vm)
+		}
+		return vm
+	}
+	extraDisk := func(vm *nutanix.VMInfo) {
+		vm.Disks = append(vm.Disks, nutanix.VMDiskInfo{DeviceIndex: 1, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 100})
+	}
+
+	cases := map[string]struct {
+		reason   string
+		spec     func(v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec
+		observed *nutanix.VMInfo
+		ignore   []string
+		want     []v1alpha1.FieldDrift
+	}{
+		"InSync": {
+			reason:   "A VM matching its spec, with an implicit boot disk, has no drift.",
+			observed: observed(nil),
+		},
+		"Sizing": {
+			reason:   "Changed vCPUs and memory are reported as drift.",
+			observed: observed(func(vm *nutanix.VMInfo) { vm.NumVCPUs, vm.MemorySizeMiB = 4, 8192 }),
+			want: []v1alpha1.FieldDrift{
+				{Field: v1alpha1.DriftFieldNumVCPUs, Desired: "2", Observed: "4"},
+				{Field: v1alpha1.DriftFieldMemorySizeMiB, Desired: "4096", Observed: "8192"},
+			},
+		},
+		"Ignored": {
+			reason:   "Fields in ignore are not reported.",
+			observed: observed(func(vm *nutanix.VMInfo) { vm.NumVCPUs, vm.PowerState = 4, "OFF" }),
+			ignore:   []string{v1alpha1.DriftFieldNumVCPUs, v1alpha1.DriftFieldPowerState},
+		},
+		"ExtraDisk": {
+			reason:   "A disk added outside the spec is reported as disk drift.",
+			observed: observed(extraDisk),
+			want:     []v1alpha1.FieldDrift{{Field: v1alpha1.DriftFieldDisks, Desired: "", Observed: "SCSI.1:100GiB"}},
+		},
+		"CategoriesAndNICs": {
+			reason: "Changed categories and NICs are reported in sorted form.",
+			observed: observed(func(vm *nutanix.VMInfo) {
+				vm.Categories = map[string]string{"Owner": "ops", "Environment": "Dev"}
+				vm.NICs = []nutanix.VMNICInfo{{SubnetUUID: "subnet-2"}, {SubnetUUID: "subnet-1"}}
+			}),
+			want: []v1alpha1.FieldDrift{
+				{Field: v1alpha1.DriftFieldNICs, Desired: "subnet-1", Observed: "subnet-1,subnet-2"},
+				{Field: v1alpha1.DriftFieldCategories, Desired: "Environment=Production", Observed: "Environment=Dev,Owner=ops"},
+			},
+		},
+		"UnsetPowerState": {
+			reason: "The power state is not compared when the spec leaves it unset.",
+			spec: func(s v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec {
+				s.PowerState = ""
+				return s
+			},
+			observed: observed(func(vm *nutanix.VMInfo) { vm.PowerState = "OFF" }),
+		},
+		"CloneKeepsSourceSizingAndDisks": {
+			reason: "Clones without sizing keep the source's sizing and disks.",
+			spec: func(s v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec {
+				s.Source = &v1alpha1.VMSource{VM: "golden"}
+				s.NumVCPUs, s.MemorySizeMiB = 0, 0
+				return s
+			},
+			observed: observed(func(vm *nutanix.VMInfo) {
+				vm.NumVCPUs, vm.MemorySizeMiB = 8, 16384
+				extraDisk(vm)
+			}),
+		},
+		"CloneWithSizing": {
+			reason: "Clones with sizing set are checked against it.",
+			spec: func(s v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec {
+				s.Source = &v1alpha1.VMSource{VM: "golden"}
+				return s
+			},
+			observed: observed(func(vm *nutanix.VMInfo) { vm.NumVCPUs = 8 }),
+			want:     []v1alpha1.FieldDrift{{Field: v1alpha1.DriftFieldNumVCPUs, Desired: "2", Observed: "8"}},
+		},
+		"IgnoredDisksWithOtherDrift": {
+			reason:   "Ignored disks are not reported when other fields drift.",
+			observed: observed(func(vm *nutanix.VMInfo) { extraDisk(vm); vm.Categories = nil }),
+			ignore:   []string{v1alpha1.DriftFieldDisks},
+			want:     []v1alpha1.FieldDrift{{Field: v1alpha1.DriftFieldCategories, Desired: "Environment=Production", Observed: ""}},
+		},
+		"RestoreKeepsDisks": {
+			reason: "Restored VMs keep the disks of their recovery point.",
+			spec: func(s v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec {
+				s.RestoreFrom = &v1alpha1.RestoreSource{RecoveryPointUUID: "rp-1"}
+				return s
+			},
+			observed: observed(extraDisk),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := spec
+			if tc.spec != nil {
+				s = tc.spec(s)
+			}
+			got := computeDrift(s, tc.observed, tc.ignore)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncomputeDrift(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKeepObserved(t *testing.T) {
+	spec := v1alpha1.VirtualMachineSpec{
+		NumVCPUs:        2,
+		MemorySizeMiB:   4096,
+		BootDisk:        &v1alpha1.BootDiskSpec{SizeGb: 40},
+		AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 1, SizeGb: 50}},
+		SubnetUUID:      "subnet-1",
+		Categories:      map[string]string{"Environment": "Production"},
+		CPU:             &v1alpha1.CPUConfig{CoresPerSocket: 2},
+	}
+	observed := &nutanix.VMInfo{
+		NumVCPUs:      4,
+		MemorySizeMiB: 8192,
+		Disks: []nutanix.VMDiskInfo{
+			{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 60},
+			{DeviceIndex: 1, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 50},
+			{DeviceIndex: 2, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 100},
+		},
+		NICs:       []nutanix.VMNICInfo{{SubnetUUID: "subnet-2"}},
+		Categories: map[string]string{"Environment": "Dev"},
+		CPU:        nutanix.VMCPUInfo{Sockets: 1, CoresPerSocket: 4, ThreadsPerCore: 1},
+	}
+
+	cases := map[string]struct {
+		reason string
+		fields []string
+		want   func(v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec
+	}{
+		"None": {
+			reason: "Without fields to keep the spec is sent as is.",
+		},
+		"Disks": {
+			reason: "Kept disks are sent as observed, including disks added outside the spec, so a category update does not remove them.",
+			fields: []string{v1alpha1.DriftFieldDisks},
+			want: func(s v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec {
+				s.BootDisk = &v1alpha1.BootDiskSpec{SizeGb: 60}
+				s.AdditionalDisks = []v1alpha1.DiskSpec{
+					{DeviceIndex: 1, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 50},
+					{DeviceIndex: 2, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 100},
+				}
+				return s
+			},
+		},
+		"SizingAndNICs": {
+			reason: "Kept sizing and NICs are sent as observed.",
+			fields: []string{v1alpha1.DriftFieldNumVCPUs, v1alpha1.DriftFieldMemorySizeMiB, v1alpha1.DriftFieldNICs},
+			want: func(s v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec {
+				s.NumVCPUs, s.MemorySizeMiB, s.SubnetUUID = 4, 8192, "subnet-2"
+				return s
+			},
+		},
+		"CategoriesAndCPU": {
+			reason: "Kept categories and CPU topology are sent as observed.",
+			fields: []string{v1alpha1.DriftFieldCategories, v1alpha1.DriftFieldCPU},
+			want: func(s v1alpha1.VirtualMachineSpec) v1alpha1.VirtualMachineSpec {
+				s.Categories = map[string]string{"Environment": "Dev"}
+				s.CPU = &v1alpha1.CPUConfig{Sockets: 1, CoresPerSocket: 4, ThreadsPerCore: 1}
+				return s
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			want := spec
+			if tc.want != nil {
+				want = tc.want(want)
+			}
+			got := keepObserved(spec, observed, tc.fields)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nkeepObserved(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(&v1alpha1.BootDiskSpec{SizeGb: 40}, spec.BootDisk); diff != "" {
+				t.Errorf("\n%s\nkeepObserved(...): modified the spec's boot disk:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFormatDisks(t *testing.T) {
+	cases := map[string]struct {
+		reason       string
+		boot         *v1alpha1.BootDiskSpec
+		desired      []v1alpha1.DiskSpec
+		observed     []nutanix.VMDiskInfo
+		wantDesired  string
+		wantObserved string
+	}{
+		"Empty": {
+			reason: "No disks render as an empty string.",
+		},
+		"SortedByBusAndIndex": {
+			reason: "Disks are ordered by bus and index, and CD-ROMs default to the IDE bus.",
+			desired: []v1alpha1.DiskSpec{
+				{DeviceIndex: 2, SizeGb: 50},
+				{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeCDROM},
+				{DeviceIndex: 1, SizeGb: 10, Bus: v1alpha1.DiskBusPCI},
+			},
+			observed: []nutanix.VMDiskInfo{
+				{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 20},
+				{DeviceIndex: 2, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 50},
+				{DeviceIndex: 1, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusPCI, SizeGb: 10},
+				{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeCDROM, Bus: v1alpha1.DiskBusIDE},
+			},
+			wantDesired:  "IDE.0:CDROM,PCI.1:10GiB,SCSI.2:50GiB",
+			wantObserved: "IDE.0:CDROM,PCI.1:10GiB,SCSI.2:50GiB",
+		},
+		"BootDiskSize": {
+			reason:       "An explicit boot disk size is compared, on the boot disk's bus.",
+			boot:         &v1alpha1.BootDiskSpec{SizeGb: 40, Bus: v1alpha1.DiskBusSATA},
+			desired:      []v1alpha1.DiskSpec{{DeviceIndex: 0, SizeGb: 50}},
+			observed:     []nutanix.VMDiskInfo{{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSATA, SizeGb: 30}, {DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 50}},
+			wantDesired:  "boot:40GiB,SCSI.0:50GiB",
+			wantObserved: "boot:30GiB,SCSI.0:50GiB",
+		},
+		"BootDiskWithoutSize": {
+			reason:       "A boot disk without an explicit size is left out.",
+			boot:         &v1alpha1.BootDiskSpec{},
+			observed:     []nutanix.VMDiskInfo{{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeDisk, Bus: v1alpha1.DiskBusSCSI, SizeGb: 30}},
+			wantDesired:  "",
+			wantObserved: "",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.wantDesired, formatDesiredDisks(tc.boot, tc.desired)); diff != "" {
+				t.Errorf("\n%s\nformatDesiredDisks(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantObserved, formatObservedDisks(tc.boot, tc.observed)); diff != "" {
+				t.Errorf("\n%s\nformatObservedDisks(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
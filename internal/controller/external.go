@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1beta1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// finalizerName keeps a managed resource around until its Prism object has
+// been deleted.
+const finalizerName = "finalizer.managedresource.crossplane.io"
+
+// errCreateIncomplete is returned when an earlier create may have succeeded
+// without its external name being recorded.
+const errCreateIncomplete = "cannot determine creation result - remove the " + meta.AnnotationKeyExternalCreatePending + " annotation if it is safe to proceed"
+
+// Event reasons recorded against managed resources other than VirtualMachine,
+// in addition to the shared create, observe and delete reasons.
+const (
+	reasonUpdated      event.Reason = "Updated"
+	reasonCannotUpdate event.Reason = "CannotUpdate"
+)
+
+// externalResource is a Kubernetes resource that manages an object in Prism.
+type externalResource interface {
+	client.Object
+
+	GetCondition(ct xpv1.ConditionType) xpv1.Condition
+	SetConditions(c ...xpv1.Condition)
+
+	// GetDatacenter returns the datacenter whose Prism Central manages the
+	// object, or "" for the ProviderConfig's default endpoint.
+	GetDatacenter() string
+}
+
+// externalObservation is the result of observing a Prism object.
+type externalObservation struct {
+	// Exists is false when the object was not found in Prism.
+	Exists bool
+
+	// UpToDate is false when the object differs from the desired state.
+	UpToDate bool
+
+	// Pending is true while the object exists but is not usable yet, e.g. an
+	// image that is still being downloaded.
+	Pending bool
+}
+
+// externalClient manages the Prism object behind a resource of type T. The
+// object is identified by the resource's crossplane.io/external-name
+// annotation, which is set from the name returned by Create. Setting the
+// annotation on a new resource imports an existing object.
+type externalClient[T externalResource] interface {
+	// Observe reports whether the object exists and is up to date, and
+	// records its observed state in the resource's status. Objects whose
+	// state cannot be observed are reported as existing and up to date.
+	Observe(ctx context.Context, ntxCli *nutanix.Client, mg T) (externalObservation, error)
+
+	// Create creates the object and returns its external name.
+	Create(ctx context.Context, ntxCli *nutanix.Client, mg T) (string, error)
+
+	// Update brings the object in line with the resource's spec.
+	Update(ctx context.Context, ntxCli *nutanix.Client, mg T) error
+
+	// Delete deletes the object. It returns an error while the object is
+	// still in use, which keeps the resource and retries later.
+	Delete(ctx context.Context, ntxCli *nutanix.Client, mg T) error
+}
+
+// externalReconciler reconciles resources of type T with their Prism objects.
+type externalReconciler[T externalResource] struct {
+	client.Client
+	kind         string
+	log          logging.Logger
+	record       event.Recorder
+	pollInterval time.Duration
+	prismLimiter *rate.Limiter
+	newObject    func() T
+	external     externalClient[T]
+}
+
+func (r *externalReconciler[T]) Reconcile(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) {
+	r.log.Debug("Reconciling Nutanix "+r.kind, "name", req.NamespacedName)
+
+	ctx, span := tracing.Start(ctx, r.kind+"Reconciler.Reconcile",
+		attribute.String("k8s.namespace", req.Namespace), attribute.String("k8s.name", req.Name))
+	defer func() { tracing.End(span, err) }()
+
+	mg := r.newObject()
+	if err := r.Get(ctx, req.NamespacedName, mg); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var pc v1beta1.ProviderConfig
+	if err := r.Get(ctx, client.ObjectKey{Name: "default"}, &pc); err != nil {
+		return r.fail(ctx, mg, reasonCannotConnect, err)
+	}
+	ntxCli, err := connectPrism(ctx, r.Client, &pc, mg.GetDatacenter(), r.prismLimiter)
+	if err != nil {
+		return r.fail(ctx, mg, reasonCannotConnect, err)
+	}
+
+	externalName := meta.GetExternalName(mg)
+	if meta.WasDeleted(mg) {
+		mg.SetConditions(xpv1.Deleting())
+		if externalName != "" && meta.FinalizerExists(mg, finalizerName) {
+			if err := r.external.Delete(ctx, ntxCli, mg); err != nil {
+				r.log.Debug("Failed to delete "+r.kind, "error", err)
+				return r.fail(ctx, mg, reasonCannotDelete, err)
+			}
+		}
+		meta.RemoveFinalizer(mg, finalizerName)
+		return reconcile.Result{}, r.Update(ctx, mg)
+	}
+	if !meta.FinalizerExists(mg, finalizerName) {
+		meta.AddFinalizer(mg, finalizerName)
+		if err := r.Update(ctx, mg); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if externalName != "" {
+		obs, err := r.external.Observe(ctx, ntxCli, mg)
+		if err != nil {
+			r.log.Debug("Failed to observe "+r.kind, "error", err)
+			return r.fail(ctx, mg, reasonCannotObserve, err)
+		}
+		if obs.Exists {
+			if !obs.UpToDate {
+				if err := r.external.Update(ctx, ntxCli, mg); err != nil {
+					r.log.Debug("Failed to update "+r.kind, "error", err)
+					return r.fail(ctx, mg, reasonCannotUpdate, err)
+				}
+				r.record.Event(mg, event.Normal(reasonUpdated, fmt.Sprintf("Updated %s %s", r.kind, externalName)))
+			}
+			if obs.Pending {
+				mg.SetConditions(xpv1.Creating(), xpv1.ReconcileSuccess())
+			} else {
+				mg.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			}
+			if err := r.Status().Update(ctx, mg); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+		}
+	}
+
+	// A create that was started but never recorded as succeeded or failed
+	// may have created the object, so it is not retried blindly.
+	if meta.ExternalCreateIncomplete(mg) {
+		return r.fail(ctx, mg, reasonCannotCreate, errors.New(errCreateIncomplete))
+	}
+	meta.SetExternalCreatePending(mg, time.Now())
+	if err := r.updateCriticalAnnotations(ctx, mg); err != nil {
+		return r.fail(ctx, mg, reasonCannotCreate, fmt.Errorf("cannot record pending create: %w", err))
+	}
+
+	r.record.Event(mg, event.Normal(reasonCreateStarted, fmt.Sprintf("Creating %s %s", r.kind, mg.GetName())))
+	name, err := r.external.Create(ctx, ntxCli, mg)
+	if err != nil {
+		r.log.Debug("Failed to create "+r.kind, "error", err)
+		meta.SetExternalCreateFailed(mg, time.Now())
+		if uerr := r.updateCriticalAnnotations(ctx, mg); uerr != nil {
+			r.log.Debug("Failed to record failed create of "+r.kind, "error", uerr)
+		}
+		return r.fail(ctx, mg, reasonCannotCreate, err)
+	}
+	r.record.Event(mg, event.Normal(reasonCreateSucceeded, fmt.Sprintf("Created %s %s", r.kind, name)))
+	meta.SetExternalName(mg, name)
+	meta.SetExternalCreateSucceeded(mg, time.Now())
+	if err := r.updateCriticalAnnotations(ctx, mg); err != nil {
+		// The pending create annotation keeps the object from being created
+		// again until the external name is set by hand.
+		return r.fail(ctx, mg, reasonCannotCreate, fmt.Errorf("cannot record external name %s: %w", name, err))
+	}
+	mg.SetConditions(xpv1.Creating(), xpv1.ReconcileSuccess())
+	if err := r.Status().Update(ctx, mg); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+}
+
+// updateCriticalAnnotations persists mg's annotations, retrying on API
+// errors, as losing the external name would orphan the Prism object. The
+// latest object is updated instead of mg, so that the status recorded during
+// this reconcile is kept for the status update that follows.
+func (r *externalReconciler[T]) updateCriticalAnnotations(ctx context.Context, mg T) error {
+	annotations := mg.GetAnnotations()
+	return retry.OnError(retry.DefaultRetry, resource.IsAPIError, func() error {
+		latest := r.newObject()
+		if err := r.Get(ctx, client.ObjectKeyFromObject(mg), latest); err != nil {
+			return err
+		}
+		meta.AddAnnotations(latest, annotations)
+		if err := r.Update(ctx, latest); err != nil {
+			return err
+		}
+		mg.SetResourceVersion(latest.GetResourceVersion())
+		return nil
+	})
+}
+
+// fail records err as a warning event and in the resource's Synced condition.
+func (r *externalReconciler[T]) fail(ctx context.Context, mg T, reason event.Reason, err error) (reconcile.Result, error) {
+	r.record.Event(mg, event.Warning(reason, err))
+	mg.SetConditions(xpv1.ReconcileError(err))
+	if uerr := r.Status().Update(ctx, mg); uerr != nil {
+		r.log.Debug("Failed to update "+r.kind+" status", "error", uerr)
+	}
+	return reconcile.Result{}, err
+}
+
+// setupExternal adds a controller that reconciles resources of kind with
+// their Prism objects through external.
+func setupExternal[T externalResource](mgr manager.Manager, o Options, kind string, newObject func() T, external externalClient[T]) error {
+	name := strings.ToLower(kind)
+	r := &externalReconciler[T]{
+		Client:       mgr.GetClient(),
+		kind:         kind,
+		log:          o.Logger.WithValues("controller", name),
+		record:       event.NewAPIRecorder(mgr.GetEventRecorderFor("managed/" + name)),
+		pollInterval: o.PollInterval,
+		prismLimiter: o.PrismRateLimiter,
+		newObject:    newObject,
+		external:     external,
+	}
+
+	opts := o.ForControllerRuntime()
+	opts.Reconciler = r
+	c, err := controller.New(name+"-controller", mgr, opts)
+	if err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: newObject()}, &handler.EnqueueRequestForObject{})
+}
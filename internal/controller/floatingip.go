@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// reconcileFloatingIP assigns a floating IP to the VM when spec.floatingIp is
+// set and releases the one it assigned when it is removed. A floating IP that
+// was released or re-associated outside the provider is replaced or
+// re-associated with the VM.
+func (r *VirtualMachineReconciler) reconcileFloatingIP(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	if vm.Spec.FloatingIP == nil {
+		return r.releaseFloatingIP(ctx, ntxCli, vm)
+	}
+	ctx, span := tracing.Start(ctx, "ReconcileFloatingIP", tracing.AttrVMUUID.String(vm.Status.VMID))
+	defer func() { tracing.End(span, err) }()
+
+	if fip := vm.Status.FloatingIP; fip != nil {
+		observed, err := ntxCli.GetFloatingIP(ctx, fip.UUID)
+		switch {
+		case errors.Is(err, nutanix.ErrNotFound):
+			vm.Status.FloatingIP = nil
+		case err != nil:
+			return err
+		case observed == nil || observed.VMUUID == vm.Status.VMID:
+			if observed != nil {
+				fip.Address = observed.Address
+			}
+			return nil
+		default:
+			if _, _, err := ntxCli.PutFloatingIP(ctx, nutanix.FloatingIPInfo{UUID: fip.UUID, Address: observed.Address, VMUUID: vm.Status.VMID}); err != nil {
+				return fmt.Errorf("cannot re-associate floating IP %s: %w", observed.Address, err)
+			}
+			fip.Address = observed.Address
+			r.record.Event(vm, event.Normal(reasonFloatingIPUpdated, fmt.Sprintf("Re-associated floating IP %s", fip.Address)))
+			return nil
+		}
+	}
+
+	subnetUUID, err := fetchSubnetUUID(ctx, ntxCli, vm.Spec.FloatingIP.ExternalSubnetName)
+	if err != nil {
+		return err
+	}
+	uuid, address, err := ntxCli.PutFloatingIP(ctx, nutanix.FloatingIPInfo{ExternalSubnetUUID: subnetUUID, VMUUID: vm.Status.VMID})
+	if err != nil {
+		return fmt.Errorf("cannot assign floating IP: %w", err)
+	}
+	vm.Status.FloatingIP = &v1alpha1.VMFloatingIPStatus{UUID: uuid, Address: address}
+	r.record.Event(vm, event.Normal(reasonFloatingIPUpdated, fmt.Sprintf("Assigned floating IP %s from %s", address, vm.Spec.FloatingIP.ExternalSubnetName)))
+	return nil
+}
+
+// releaseFloatingIP releases the floating IP assigned to the VM, if any.
+func (r *VirtualMachineReconciler) releaseFloatingIP(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) error {
+	fip := vm.Status.FloatingIP
+	if fip == nil {
+		return nil
+	}
+	if err := ntxCli.DeleteFloatingIP(ctx, fip.UUID); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return fmt.Errorf("cannot release floating IP %s: %w", fip.Address, err)
+	}
+	vm.Status.FloatingIP = nil
+	r.record.Event(vm, event.Normal(reasonFloatingIPUpdated, fmt.Sprintf("Released floating IP %s", fip.Address)))
+	return nil
+}
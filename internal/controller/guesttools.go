@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// reconcileGuestTools brings the VM's Nutanix Guest Tools state in line with
+// spec.guestTools and reports the observed state in status.
+func (r *VirtualMachineReconciler) reconcileGuestTools(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	ctx, span := tracing.Start(ctx, "ReconcileGuestTools", tracing.AttrVMUUID.String(vm.Status.VMID))
+	defer func() { tracing.End(span, err) }()
+
+	info, err := ntxCli.GetGuestTools(ctx, vm.Status.VMID)
+	if err != nil {
+		return fmt.Errorf("cannot get guest tools state: %w", err)
+	}
+	if info == nil {
+		return nil
+	}
+	vm.Status.GuestTools = &v1alpha1.GuestToolsStatus{
+		Enabled:      info.Enabled,
+		ISOMounted:   info.ISOMounted,
+		Installed:    info.Installed,
+		Version:      info.Version,
+		Reachable:    info.Reachable,
+		Capabilities: info.Capabilities,
+	}
+
+	gt := vm.Spec.GuestTools
+	if gt == nil {
+		return nil
+	}
+	switch {
+	case gt.Enabled && (!info.Enabled || !sameCapabilities(gt.Capabilities, info.Capabilities)):
+		if err := ntxCli.EnableGuestTools(ctx, vm.Status.VMID, gt.Capabilities); err != nil {
+			return fmt.Errorf("cannot enable guest tools: %w", err)
+		}
+		r.record.Event(vm, event.Normal(reasonGuestToolsUpdated, fmt.Sprintf("Enabled guest tools with capabilities [%s]", strings.Join(gt.Capabilities, ", "))))
+	case !gt.Enabled && info.Enabled:
+		if err := ntxCli.DisableGuestTools(ctx, vm.Status.VMID); err != nil {
+			return fmt.Errorf("cannot disable guest tools: %w", err)
+		}
+		r.record.Event(vm, event.Normal(reasonGuestToolsUpdated, "Disabled guest tools"))
+		return nil
+	}
+	if gt.Enabled && gt.Mount && !info.Installed && !info.ISOMounted {
+		if err := ntxCli.MountGuestTools(ctx, vm.Status.VMID); err != nil {
+			return fmt.Errorf("cannot mount guest tools: %w", err)
+		}
+		r.record.Event(vm, event.Normal(reasonGuestToolsUpdated, "Mounted guest tools installer"))
+	}
+	return nil
+}
+
+// validateGuestTools checks that a VM mounting the NGT installer declares an
+// empty CD-ROM for Prism to mount it on. Clones and restored VMs keep the
+// drives of their source, which the spec does not list.
+func validateGuestTools(vm *v1alpha1.VirtualMachine) error {
+	gt := vm.Spec.GuestTools
+	if gt == nil || !gt.Enabled || !gt.Mount || vm.Spec.Source != nil || vm.Spec.RestoreFrom != nil {
+		return nil
+	}
+	for _, d := range vm.Spec.AdditionalDisks {
+		if d.DeviceType == v1alpha1.DeviceTypeCDROM && d.ImageUUID == "" && d.ImageName == "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("guestTools.mount requires an additionalDisks entry of deviceType CDROM without an image to mount the installer on")
+}
+
+// sameCapabilities reports whether a and b hold the same NGT capabilities in
+// any order.
+func sameCapabilities(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	as, bs := append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(as)
+	sort.Strings(bs)
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
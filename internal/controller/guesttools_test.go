@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+)
+
+func TestValidateGuestTools(t *testing.T) {
+	mount := &v1alpha1.GuestTools{Enabled: true, Mount: true}
+
+	cases := map[string]struct {
+		reason string
+		spec   v1alpha1.VirtualMachineSpec
+		want   string
+	}{
+		"NoMount": {
+			reason: "VMs not mounting the installer need no CD-ROM.",
+			spec:   v1alpha1.VirtualMachineSpec{GuestTools: &v1alpha1.GuestTools{Enabled: true}},
+		},
+		"EmptyCDROM": {
+			reason: "The installer is mounted on an empty CD-ROM.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestTools:      mount,
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeCDROM}},
+			},
+		},
+		"NoCDROM": {
+			reason: "Mounting the installer without a CD-ROM is rejected.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestTools:      mount,
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 1, SizeGb: 50}},
+			},
+			want: "guestTools.mount requires an additionalDisks entry of deviceType CDROM without an image to mount the installer on",
+		},
+		"CDROMWithImage": {
+			reason: "A CD-ROM holding an image cannot take the installer.",
+			spec: v1alpha1.VirtualMachineSpec{
+				GuestTools:      mount,
+				AdditionalDisks: []v1alpha1.DiskSpec{{DeviceIndex: 0, DeviceType: v1alpha1.DeviceTypeCDROM, ImageName: "virtio"}},
+			},
+			want: "guestTools.mount requires an additionalDisks entry of deviceType CDROM without an image to mount the installer on",
+		},
+		"Clone": {
+			reason: "Clones keep the drives of their source, which are not checked.",
+			spec:   v1alpha1.VirtualMachineSpec{GuestTools: mount, Source: &v1alpha1.VMSource{VM: "golden"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateGuestTools(&v1alpha1.VirtualMachine{Spec: tc.spec})
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidateGuestTools(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
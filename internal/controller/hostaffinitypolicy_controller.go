@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupHostAffinityPolicy adds a controller that reconciles HostAffinityPolicy resources.
+func SetupHostAffinityPolicy(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.HostAffinityPolicy](mgr, o, "HostAffinityPolicy",
+		func() *v1alpha1.HostAffinityPolicy { return &v1alpha1.HostAffinityPolicy{} }, &hostAffinityPolicyExternal{})
+}
+
+// hostAffinityPolicyExternal manages VM-host affinity policies. The external
+// name is the policy UUID.
+type hostAffinityPolicyExternal struct{}
+
+func (e *hostAffinityPolicyExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.HostAffinityPolicy) (externalObservation, error) {
+	observed, err := ntxCli.GetHostAffinityPolicy(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.NumVMs = observed.NumVMs
+	cr.Status.NumHosts = observed.NumHosts
+	cr.Status.NumCompliantVMs = observed.NumCompliantVMs
+	upToDate := observed.Name == cr.Spec.Name &&
+		observed.Description == cr.Spec.Description &&
+		reflect.DeepEqual(observed.VMCategories, cr.Spec.VMCategories) &&
+		reflect.DeepEqual(observed.HostCategories, cr.Spec.HostCategories)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *hostAffinityPolicyExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.HostAffinityPolicy) (string, error) {
+	policy, err := hostAffinityPolicyInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.PutHostAffinityPolicy(ctx, policy)
+}
+
+func (e *hostAffinityPolicyExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.HostAffinityPolicy) error {
+	policy, err := hostAffinityPolicyInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return err
+	}
+	policy.UUID = meta.GetExternalName(cr)
+	_, err = ntxCli.PutHostAffinityPolicy(ctx, policy)
+	return err
+}
+
+func (e *hostAffinityPolicyExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.HostAffinityPolicy) error {
+	if err := ntxCli.DeleteHostAffinityPolicy(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// hostAffinityPolicyInfo checks that the categories of cr exist and returns
+// the VM-host affinity policy it describes.
+func hostAffinityPolicyInfo(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.HostAffinityPolicy) (nutanix.HostAffinityPolicyInfo, error) {
+	policy := nutanix.HostAffinityPolicyInfo{
+		Name:           cr.Spec.Name,
+		Description:    cr.Spec.Description,
+		VMCategories:   cr.Spec.VMCategories,
+		HostCategories: cr.Spec.HostCategories,
+	}
+	for _, categories := range []map[string]string{cr.Spec.VMCategories, cr.Spec.HostCategories} {
+		keys := make([]string, 0, len(categories))
+		for k := range categories {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ok, err := ntxCli.CategoryValueExists(ctx, k, categories[k])
+			if err != nil {
+				return policy, fmt.Errorf("cannot check category value %s=%s: %w", k, categories[k], err)
+			}
+			if !ok {
+				return policy, fmt.Errorf("category value %s=%s does not exist", k, categories[k])
+			}
+		}
+	}
+	return policy, nil
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupImage adds a controller that reconciles Image resources.
+func SetupImage(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.Image](mgr, o, "Image",
+		func() *v1alpha1.Image { return &v1alpha1.Image{} }, &imageExternal{})
+}
+
+// imageExternal manages Prism images. The external name is the image UUID.
+type imageExternal struct{}
+
+func (e *imageExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Image) (externalObservation, error) {
+	observed, err := ntxCli.GetImage(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.State = observed.State
+	cr.Status.SizeBytes = observed.SizeBytes
+	if observed.State == "ERROR" {
+		return externalObservation{}, fmt.Errorf("image %s is in state ERROR", observed.UUID)
+	}
+	upToDate := observed.Name == cr.Spec.Name &&
+		observed.Description == cr.Spec.Description &&
+		formatCategories(observed.Categories) == formatCategories(cr.Spec.Categories)
+	return externalObservation{Exists: true, UpToDate: upToDate, Pending: observed.State == "PENDING"}, nil
+}
+
+func (e *imageExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Image) (string, error) {
+	src := cr.Spec.Source
+	n := 0
+	for _, set := range []bool{src.URL != "", src.VMDisk != nil, src.ImageUUID != "", src.ImageName != ""} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return "", fmt.Errorf("exactly one of source url, vmDisk, imageUuid or imageName must be set")
+	}
+
+	sourceImageUUID := src.ImageUUID
+	if src.ImageName != "" {
+		images, err := ntxCli.ListImages(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, img := range images {
+			if img.Name == src.ImageName {
+				sourceImageUUID = img.UUID
+				break
+			}
+		}
+		if sourceImageUUID == "" {
+			return "", fmt.Errorf("source image %s not found", src.ImageName)
+		}
+	}
+
+	clusterUUIDs := make([]string, 0, len(cr.Spec.ClusterNames))
+	for _, name := range cr.Spec.ClusterNames {
+		uuid, err := fetchClusterUUID(ctx, ntxCli, name)
+		if err != nil {
+			return "", err
+		}
+		clusterUUIDs = append(clusterUUIDs, uuid)
+	}
+	return ntxCli.CreateImage(ctx, cr.Spec, sourceImageUUID, clusterUUIDs)
+}
+
+func (e *imageExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Image) error {
+	return ntxCli.UpdateImage(ctx, meta.GetExternalName(cr), cr.Spec)
+}
+
+func (e *imageExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Image) error {
+	if err := ntxCli.DeleteImage(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
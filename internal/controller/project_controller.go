@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/apis/v1beta1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupProject adds a controller that reconciles Project resources.
+func SetupProject(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.Project](mgr, o, "Project",
+		func() *v1alpha1.Project { return &v1alpha1.Project{} }, &projectExternal{kube: mgr.GetClient()})
+}
+
+// projectExternal manages Prism projects. The external name is the project UUID.
+type projectExternal struct {
+	kube client.Client
+}
+
+func (e *projectExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Project) (externalObservation, error) {
+	observed, err := ntxCli.GetProject(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	refs, err := resolveProjectRefs(ctx, ntxCli, cr.Spec)
+	if err != nil {
+		return externalObservation{}, err
+	}
+	upToDate := observed.Name == cr.Spec.Name &&
+		observed.Description == cr.Spec.Description &&
+		reflect.DeepEqual(observed.Quota, cr.Spec.Quota) &&
+		reflect.DeepEqual(sortedRefs(observed.Refs), sortedRefs(refs)) &&
+		reflect.DeepEqual(sortedMemberGroups(observed.MemberGroups), sortedMemberGroups(cr.Spec.MemberGroups))
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *projectExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Project) (string, error) {
+	refs, err := resolveProjectRefs(ctx, ntxCli, cr.Spec)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.CreateProject(ctx, cr.Spec, refs)
+}
+
+func (e *projectExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Project) error {
+	refs, err := resolveProjectRefs(ctx, ntxCli, cr.Spec)
+	if err != nil {
+		return err
+	}
+	return ntxCli.UpdateProject(ctx, meta.GetExternalName(cr), cr.Spec, refs)
+}
+
+func (e *projectExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Project) error {
+	var pc v1beta1.ProviderConfig
+	if err := e.kube.Get(ctx, client.ObjectKey{Name: "default"}, &pc); err != nil {
+		return err
+	}
+	var vms v1alpha1.VirtualMachineList
+	if err := e.kube.List(ctx, &vms); err != nil {
+		return fmt.Errorf("cannot list VirtualMachines: %w", err)
+	}
+	for i := range vms.Items {
+		vm := &vms.Items[i]
+		if usesProject(&pc, vm, cr) {
+			return fmt.Errorf("project %s is still used by VirtualMachine %s/%s", cr.Spec.Name, vm.Namespace, vm.Name)
+		}
+	}
+	if err := ntxCli.DeleteProject(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// resolveProjectRefs resolves the cluster, subnet and image names in a
+// project's spec to their Prism UUIDs.
+func resolveProjectRefs(ctx context.Context, ntxCli *nutanix.Client, spec v1alpha1.ProjectSpec) (nutanix.ProjectRefs, error) {
+	var refs nutanix.ProjectRefs
+	for _, name := range spec.ClusterNames {
+		uuid, err := fetchClusterUUID(ctx, ntxCli, name)
+		if err != nil {
+			return refs, err
+		}
+		refs.ClusterUUIDs = append(refs.ClusterUUIDs, uuid)
+	}
+
+	if len(spec.SubnetNames) > 0 {
+		subnets, err := ntxCli.ListSubnets(ctx)
+		if err != nil {
+			return refs, err
+		}
+		byName := make(map[string]string, len(subnets))
+		for _, sn := range subnets {
+			byName[sn.Name] = sn.UUID
+		}
+		for _, name := range spec.SubnetNames {
+			uuid, ok := byName[name]
+			if !ok {
+				return refs, fmt.Errorf("subnet %s not found", name)
+			}
+			refs.SubnetUUIDs = append(refs.SubnetUUIDs, uuid)
+		}
+		if spec.DefaultSubnetName != "" {
+			uuid, ok := byName[spec.DefaultSubnetName]
+			if !ok {
+				return refs, fmt.Errorf("default subnet %s not found", spec.DefaultSubnetName)
+			}
+			refs.DefaultSubnetUUID = uuid
+		}
+	}
+
+	if len(spec.ImageNames) > 0 {
+		images, err := ntxCli.ListImages(ctx)
+		if err != nil {
+			return refs, err
+		}
+		byName := make(map[string]string, len(images))
+		for _, img := range images {
+			byName[img.Name] = img.UUID
+		}
+		for _, name := range spec.ImageNames {
+			uuid, ok := byName[name]
+			if !ok {
+				return refs, fmt.Errorf("image %s not found", name)
+			}
+			refs.ImageUUIDs = append(refs.ImageUUIDs, uuid)
+		}
+	}
+	return refs, nil
+}
+
+// usesProject reports whether vm is placed in the project of cr, through its
+// ProjectRef, project name or UUID, or the project its LoB is mapped to.
+func usesProject(pc *v1beta1.ProviderConfig, vm *v1alpha1.VirtualMachine, cr *v1alpha1.Project) bool {
+	if ref := vm.Spec.ProjectRef; ref != nil {
+		return ref.Name == cr.Name
+	}
+	if uuid := meta.GetExternalName(cr); uuid != "" && vm.Spec.ProjectUUID == uuid {
+		return true
+	}
+	name := vm.Spec.ProjectName
+	if name == "" {
+		name = pc.Spec.LoBProjects[vm.Spec.LoB]
+	}
+	return name != "" && name == cr.Spec.Name
+}
+
+// sortedMemberGroups returns a sorted copy of groups, as Prism does not keep
+// their order.
+func sortedMemberGroups(groups []v1alpha1.ProjectMemberGroup) []v1alpha1.ProjectMemberGroup {
+	sorted := make([]v1alpha1.ProjectMemberGroup, len(groups))
+	copy(sorted, groups)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Group != sorted[j].Group {
+			return sorted[i].Group < sorted[j].Group
+		}
+		return sorted[i].Role < sorted[j].Role
+	})
+	return sorted
+}
+
+// sortedRefs returns refs with its UUID lists sorted, for comparison.
+func sortedRefs(refs nutanix.ProjectRefs) nutanix.ProjectRefs {
+	for _, l := range [][]string{refs.ClusterUUIDs, refs.SubnetUUIDs, refs.ImageUUIDs} {
+		sort.Strings(l)
+	}
+	return refs
+}
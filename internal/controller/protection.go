@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// applyProtection adds the categories selected by the VM's protection policy
+// to its spec so that Prism protects it, and returns them. A policy category
+// the spec sets to a different value is rejected.
+func (r *VirtualMachineReconciler) applyProtection(ctx context.Context, vm *v1alpha1.VirtualMachine) (_ map[string]string, err error) {
+	p := vm.Spec.Protection
+	if p == nil {
+		return nil, nil
+	}
+	ctx, span := tracing.Start(ctx, "ApplyProtection")
+	defer func() { tracing.End(span, err) }()
+
+	var policy v1alpha1.ProtectionPolicy
+	if err := r.Get(ctx, client.ObjectKey{Name: p.PolicyRef.Name}, &policy); err != nil {
+		return nil, fmt.Errorf("cannot get ProtectionPolicy %s: %w", p.PolicyRef.Name, err)
+	}
+	if policy.Spec.Datacenter != vm.Spec.Datacenter {
+		return nil, fmt.Errorf("ProtectionPolicy %s protects datacenter %q, not %q", policy.Name, policy.Spec.Datacenter, vm.Spec.Datacenter)
+	}
+	categories := make(map[string]string, len(vm.Spec.Categories)+len(policy.Spec.Categories))
+	for k, v := range vm.Spec.Categories {
+		categories[k] = v
+	}
+	for k, v := range policy.Spec.Categories {
+		if existing, ok := categories[k]; ok && existing != v {
+			return nil, fmt.Errorf("category %s=%s conflicts with %s=%s required by ProtectionPolicy %s", k, existing, k, v, policy.Name)
+		}
+		categories[k] = v
+	}
+	vm.Spec.Categories = categories
+	return policy.Spec.Categories, nil
+}
+
+// reconcileProtection reports the VM's replication state in status.
+func (r *VirtualMachineReconciler) reconcileProtection(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	ctx, span := tracing.Start(ctx, "ReconcileProtection", tracing.AttrVMUUID.String(vm.Status.VMID))
+	defer func() { tracing.End(span, err) }()
+
+	info, err := ntxCli.GetVMProtection(ctx, vm.Status.VMID)
+	if err != nil {
+		return fmt.Errorf("cannot get protection state: %w", err)
+	}
+	if info == nil {
+		return nil
+	}
+	if info.PolicyName == "" {
+		vm.Status.Protection = nil
+		return nil
+	}
+	vm.Status.Protection = &v1alpha1.VMProtectionStatus{
+		Policy:          info.PolicyName,
+		State:           info.State,
+		ReplicationType: info.ReplicationType,
+	}
+	if info.LastReplicationTime != nil {
+		t := metav1.NewTime(*info.LastReplicationTime)
+		vm.Status.Protection.LastReplicationTime = &t
+	}
+	return nil
+}
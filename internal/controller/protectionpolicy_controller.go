@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/apis/v1beta1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupProtectionPolicy adds a controller that reconciles ProtectionPolicy resources.
+func SetupProtectionPolicy(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.ProtectionPolicy](mgr, o, "ProtectionPolicy",
+		func() *v1alpha1.ProtectionPolicy { return &v1alpha1.ProtectionPolicy{} },
+		&protectionPolicyExternal{kube: mgr.GetClient(), limiter: o.PrismRateLimiter})
+}
+
+// protectionPolicyExternal manages Prism protection policies. The external
+// name is the policy UUID.
+type protectionPolicyExternal struct {
+	kube    client.Client
+	limiter *rate.Limiter
+}
+
+func (e *protectionPolicyExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ProtectionPolicy) (externalObservation, error) {
+	observed, err := ntxCli.GetProtectionPolicy(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.ProtectedVMs = observed.ProtectedVMs
+	desired, err := e.policyInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return externalObservation{}, err
+	}
+	upToDate := observed.Name == desired.Name &&
+		observed.Description == desired.Description &&
+		formatCategories(observed.Categories) == formatCategories(desired.Categories) &&
+		reflect.DeepEqual(observed.Rules, desired.Rules)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *protectionPolicyExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ProtectionPolicy) (string, error) {
+	policy, err := e.policyInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.CreateProtectionPolicy(ctx, policy)
+}
+
+func (e *protectionPolicyExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ProtectionPolicy) error {
+	policy, err := e.policyInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return err
+	}
+	return ntxCli.UpdateProtectionPolicy(ctx, meta.GetExternalName(cr), policy)
+}
+
+func (e *protectionPolicyExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ProtectionPolicy) error {
+	var vms v1alpha1.VirtualMachineList
+	if err := e.kube.List(ctx, &vms); err != nil {
+		return fmt.Errorf("cannot list VirtualMachines: %w", err)
+	}
+	for _, vm := range vms.Items {
+		if p := vm.Spec.Protection; p != nil && p.PolicyRef.Name == cr.Name {
+			return fmt.Errorf("protection policy %s is still used by VirtualMachine %s/%s", cr.Spec.Name, vm.Namespace, vm.Name)
+		}
+	}
+	if err := ntxCli.DeleteProtectionPolicy(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// policyInfo returns the Prism protection policy described by cr, with its
+// locations resolved to availability zones and clusters.
+func (e *protectionPolicyExternal) policyInfo(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ProtectionPolicy) (nutanix.ProtectionPolicyInfo, error) {
+	policy := nutanix.ProtectionPolicyInfo{Name: cr.Spec.Name, Description: cr.Spec.Description, Categories: cr.Spec.Categories}
+	if cr.Spec.Source.Datacenter != "" && cr.Spec.Source.Datacenter != cr.Spec.Datacenter {
+		return policy, fmt.Errorf("source datacenter %q must be the policy's datacenter %q", cr.Spec.Source.Datacenter, cr.Spec.Datacenter)
+	}
+	var pc v1beta1.ProviderConfig
+	if err := e.kube.Get(ctx, client.ObjectKey{Name: "default"}, &pc); err != nil {
+		return policy, err
+	}
+	azs := &availabilityZones{kube: e.kube, pc: &pc, limiter: e.limiter, local: ntxCli, datacenter: cr.Spec.Datacenter}
+	sourceAZ, sourceCluster, err := azs.resolve(ctx, cr.Spec.Source)
+	if err != nil {
+		return policy, fmt.Errorf("cannot resolve source: %w", err)
+	}
+	for i, t := range cr.Spec.Targets {
+		if _, err := replicationType(t.RPO.Duration); err != nil {
+			return policy, fmt.Errorf("target %d: %w", i, err)
+		}
+		targetAZ, targetCluster, err := azs.resolve(ctx, t.ProtectionLocation)
+		if err != nil {
+			return policy, fmt.Errorf("cannot resolve target %d: %w", i, err)
+		}
+		if targetAZ == sourceAZ && targetCluster == sourceCluster {
+			return policy, fmt.Errorf("target %d is the same as the source", i)
+		}
+		policy.Rules = append(policy.Rules, nutanix.ProtectionRuleInfo{
+			SourceAZURL:       sourceAZ,
+			SourceClusterUUID: sourceCluster,
+			TargetAZURL:       targetAZ,
+			TargetClusterUUID: targetCluster,
+			RPOSeconds:        int64(t.RPO.Duration / time.Second),
+			LocalRetention:    t.Retention.Local,
+			RemoteRetention:   t.Retention.Remote,
+		})
+	}
+	return policy, nil
+}
+
+// replicationType returns the replication type Prism uses for rpo, or an
+// error if no replication type supports it.
+func replicationType(rpo time.Duration) (string, error) {
+	switch {
+	case rpo == 0:
+		return v1alpha1.ReplicationTypeSynchronous, nil
+	case rpo >= time.Minute && rpo <= 15*time.Minute:
+		return v1alpha1.ReplicationTypeNearSync, nil
+	case rpo >= time.Hour:
+		return v1alpha1.ReplicationTypeAsync, nil
+	}
+	return "", fmt.Errorf("rpo %s is not supported: use 0 for synchronous, 1m to 15m for NearSync or 1h or more for asynchronous replication", rpo)
+}
+
+// availabilityZones resolves ProtectionLocations against the availability
+// zones known to the local Prism Central, connecting to remote Prism
+// Centrals to resolve their cluster names.
+type availabilityZones struct {
+	kube    client.Client
+	pc      *v1beta1.ProviderConfig
+	limiter *rate.Limiter
+
+	// local is connected to the Prism Central of datacenter.
+	local      *nutanix.Client
+	datacenter string
+
+	known []nutanix.AvailabilityZoneInfo
+}
+
+// resolve returns the availability zone URL and, if loc names a cluster, the
+// cluster UUID of loc.
+func (a *availabilityZones) resolve(ctx context.Context, loc v1alpha1.ProtectionLocation) (azURL, clusterUUID string, err error) {
+	dc := loc.Datacenter
+	if dc == "" {
+		dc = a.datacenter
+	}
+	endpoint := a.local.Endpoint
+	if dc != a.datacenter {
+		var ok bool
+		if endpoint, ok = a.pc.Spec.PrismCentralEndpoints[dc]; !ok {
+			return "", "", fmt.Errorf("datacenter '%s' not found in ProviderConfig's PrismCentralEndpoints map", dc)
+		}
+	}
+	if a.known == nil {
+		if a.known, err = a.local.ListAvailabilityZones(ctx); err != nil {
+			return "", "", err
+		}
+	}
+	for _, az := range a.known {
+		if az.Endpoint == endpoint {
+			azURL = az.URL
+		}
+	}
+	if azURL == "" {
+		return "", "", fmt.Errorf("the Prism Central of datacenter %q (%s) is not an availability zone of %s", dc, endpoint, a.local.Endpoint)
+	}
+	if loc.ClusterName == "" {
+		return azURL, "", nil
+	}
+
+	ntxCli := a.local
+	if dc != a.datacenter {
+		if ntxCli, err = connectPrism(ctx, a.kube, a.pc, dc, a.limiter); err != nil {
+			return "", "", err
+		}
+	}
+	if clusterUUID, err = fetchClusterUUID(ctx, ntxCli, loc.ClusterName); err != nil {
+		return "", "", err
+	}
+	return azURL, clusterUUID, nil
+}
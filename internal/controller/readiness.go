@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+const (
+	defaultGuestReadinessTimeout = 15 * time.Minute
+	// guestProbeTimeout bounds all probes of a reconcile together, so that
+	// unreachable guests do not hold up the controller's workers.
+	guestProbeTimeout = 2 * time.Second
+
+	// guestRetryInterval is how soon a VM whose guest is not yet ready is
+	// checked again, independent of the poll interval.
+	guestRetryInterval = 15 * time.Second
+)
+
+// guestProbeClient performs HTTP readiness probes against guests, which
+// commonly serve self-signed certificates.
+var guestProbeClient = &http.Client{
+	Transport: tracing.Transport(&http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // #nosec G402 -- probing guest health only
+	}),
+}
+
+// guestIP returns the IP address of the NIC at index. It returns an error if
+// the VM has been observed with fewer NICs.
+func guestIP(observed *nutanix.VMInfo, index int) (string, error) {
+	if observed == nil || len(observed.NICs) == 0 {
+		return "", nil
+	}
+	if index < 0 || index >= len(observed.NICs) {
+		return "", fmt.Errorf("guestReadiness.nicIndex %d is out of range, the VM has %d NICs", index, len(observed.NICs))
+	}
+	return observed.NICs[index].IPAddress, nil
+}
+
+// checkGuestReadiness evaluates the VM's guest readiness checks and returns
+// whether the guest is ready together with the GuestReady condition to set.
+// The guest IP is only waited for when waitForIp, tcpPort or httpGet is set.
+// It returns an error if the checks cannot be evaluated.
+func checkGuestReadiness(ctx context.Context, vm *v1alpha1.VirtualMachine, observed *nutanix.VMInfo) (bool, xpv1.Condition, error) {
+	gr := vm.Spec.GuestReadiness
+	if gr == nil {
+		return true, v1alpha1.GuestUnknown(), nil
+	}
+
+	ip, err := guestIP(observed, gr.NICIndex)
+	if err != nil {
+		return false, xpv1.Condition{}, err
+	}
+	vm.Status.GuestIP = ip
+	if !gr.WaitForIP && gr.TCPPort == 0 && gr.HTTPGet == nil {
+		return true, v1alpha1.GuestReady("no guest readiness checks are set"), nil
+	}
+	if ip == "" {
+		return false, v1alpha1.GuestNotReady(v1alpha1.ReasonWaitingForIP, fmt.Sprintf("waiting for an IP address on NIC %d", gr.NICIndex)), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, guestProbeTimeout)
+	defer cancel()
+
+	if gr.TCPPort != 0 {
+		addr := net.JoinHostPort(ip, strconv.Itoa(gr.TCPPort))
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return false, v1alpha1.GuestNotReady(v1alpha1.ReasonWaitingForProbe, fmt.Sprintf("TCP %s: %v", addr, err)), nil
+		}
+		_ = conn.Close()
+	}
+
+	if h := gr.HTTPGet; h != nil {
+		scheme, path := "http", h.Path
+		if h.Scheme == "HTTPS" {
+			scheme = "https"
+		}
+		if path == "" {
+			path = "/"
+		}
+		url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(ip, strconv.Itoa(h.Port)), path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return false, v1alpha1.GuestNotReady(v1alpha1.ReasonWaitingForProbe, err.Error()), nil
+		}
+		resp, err := guestProbeClient.Do(req)
+		if err != nil {
+			return false, v1alpha1.GuestNotReady(v1alpha1.ReasonWaitingForProbe, fmt.Sprintf("GET %s: %v", url, err)), nil
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return false, v1alpha1.GuestNotReady(v1alpha1.ReasonWaitingForProbe, fmt.Sprintf("GET %s: %s", url, resp.Status)), nil
+		}
+	}
+
+	return true, v1alpha1.GuestReady("guest reachable at " + ip), nil
+}
+
+// guestReadinessTimedOut reports whether the VM has waited longer than its
+// guest readiness timeout since it was created or adopted.
+func guestReadinessTimedOut(vm *v1alpha1.VirtualMachine) bool {
+	if vm.Status.CreationTime == nil {
+		return false
+	}
+	timeout := defaultGuestReadinessTimeout
+	if gr := vm.Spec.GuestReadiness; gr != nil && gr.Timeout != nil {
+		timeout = gr.Timeout.Duration
+	}
+	return time.Since(vm.Status.CreationTime.Time) > timeout
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+func TestCheckGuestReadiness(t *testing.T) {
+	noIP := &nutanix.VMInfo{NICs: []nutanix.VMNICInfo{{SubnetUUID: "subnet-1"}}}
+
+	type want struct {
+		ready bool
+		cond  xpv1.Condition
+		err   string
+	}
+	cases := map[string]struct {
+		reason   string
+		gr       *v1alpha1.GuestReadiness
+		observed *nutanix.VMInfo
+		want     want
+	}{
+		"NoReadiness": {
+			reason:   "Without guestReadiness the guest is not checked.",
+			observed: noIP,
+			want:     want{ready: true, cond: v1alpha1.GuestUnknown()},
+		},
+		"NoChecks": {
+			reason:   "The IP is not waited for unless waitForIp or a probe is set.",
+			gr:       &v1alpha1.GuestReadiness{},
+			observed: noIP,
+			want:     want{ready: true, cond: v1alpha1.GuestReady("no guest readiness checks are set")},
+		},
+		"WaitForIP": {
+			reason:   "waitForIp waits until the NIC reports an IP.",
+			gr:       &v1alpha1.GuestReadiness{WaitForIP: true},
+			observed: noIP,
+			want:     want{cond: v1alpha1.GuestNotReady(v1alpha1.ReasonWaitingForIP, "waiting for an IP address on NIC 0")},
+		},
+		"ProbeImpliesIP": {
+			reason:   "A probe needs the guest IP, so it implies waitForIp.",
+			gr:       &v1alpha1.GuestReadiness{TCPPort: 22},
+			observed: noIP,
+			want:     want{cond: v1alpha1.GuestNotReady(v1alpha1.ReasonWaitingForIP, "waiting for an IP address on NIC 0")},
+		},
+		"IPReported": {
+			reason:   "waitForIp is met once the NIC reports an IP.",
+			gr:       &v1alpha1.GuestReadiness{WaitForIP: true},
+			observed: &nutanix.VMInfo{NICs: []nutanix.VMNICInfo{{IPAddress: "10.0.0.5"}}},
+			want:     want{ready: true, cond: v1alpha1.GuestReady("guest reachable at 10.0.0.5")},
+		},
+		"NICIndexOutOfRange": {
+			reason:   "A nicIndex beyond the observed NICs is an error.",
+			gr:       &v1alpha1.GuestReadiness{WaitForIP: true, NICIndex: 1},
+			observed: noIP,
+			want:     want{err: "guestReadiness.nicIndex 1 is out of range, the VM has 1 NICs"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			vm := &v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{GuestReadiness: tc.gr}}
+			var got want
+			var err error
+			got.ready, got.cond, err = checkGuestReadiness(context.Background(), vm, tc.observed)
+			if err != nil {
+				got.err = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\ncheckGuestReadiness(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
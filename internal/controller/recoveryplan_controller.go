@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/apis/v1beta1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupRecoveryPlan adds a controller that reconciles RecoveryPlan resources.
+func SetupRecoveryPlan(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.RecoveryPlan](mgr, o, "RecoveryPlan",
+		func() *v1alpha1.RecoveryPlan { return &v1alpha1.RecoveryPlan{} },
+		&recoveryPlanExternal{kube: mgr.GetClient(), limiter: o.PrismRateLimiter})
+}
+
+// recoveryPlanActions maps the annotations that run a recovery plan to the
+// job action they start.
+var recoveryPlanActions = []struct {
+	annotation string
+	action     string
+}{
+	{v1alpha1.AnnotationTestFailover, v1alpha1.RecoveryPlanActionTestFailover},
+	{v1alpha1.AnnotationTestFailoverCleanup, v1alpha1.RecoveryPlanActionCleanup},
+}
+
+// recoveryPlanExternal manages Prism recovery plans. The external name is the
+// plan UUID. Jobs requested through annotations are started by Update.
+type recoveryPlanExternal struct {
+	kube    client.Client
+	limiter *rate.Limiter
+}
+
+func (e *recoveryPlanExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RecoveryPlan) (externalObservation, error) {
+	observed, err := ntxCli.GetRecoveryPlan(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if err := refreshRecoveryPlanJobs(ctx, ntxCli, cr); err != nil {
+		return externalObservation{}, err
+	}
+	action, _ := pendingRecoveryPlanJob(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: action == ""}, nil
+	}
+	desired, err := e.planInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return externalObservation{}, err
+	}
+	upToDate := action == "" &&
+		observed.Name == desired.Name &&
+		observed.Description == desired.Description &&
+		observed.PrimaryAZURL == desired.PrimaryAZURL &&
+		observed.RecoveryAZURL == desired.RecoveryAZURL &&
+		reflect.DeepEqual(observed.Stages, desired.Stages) &&
+		reflect.DeepEqual(observed.NetworkMappings, desired.NetworkMappings)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *recoveryPlanExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RecoveryPlan) (string, error) {
+	plan, err := e.planInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.CreateRecoveryPlan(ctx, plan)
+}
+
+func (e *recoveryPlanExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RecoveryPlan) error {
+	plan, err := e.planInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return err
+	}
+	uuid := meta.GetExternalName(cr)
+	if err := ntxCli.UpdateRecoveryPlan(ctx, uuid, plan); err != nil {
+		return err
+	}
+
+	action, request := pendingRecoveryPlanJob(cr)
+	if action == "" {
+		return nil
+	}
+	// The job is claimed in status before it is started, so that a failed
+	// status write after the start cannot run the same request twice.
+	previous := recoveryPlanJob(cr, action)
+	var restore *v1alpha1.RecoveryPlanJobStatus
+	if previous != nil {
+		p := *previous
+		restore = &p
+	}
+	now := metav1.Now()
+	setRecoveryPlanJob(cr, v1alpha1.RecoveryPlanJobStatus{
+		Action:    action,
+		Request:   request,
+		State:     v1alpha1.RecoveryPlanJobStarting,
+		StartTime: &now,
+	})
+	if err := e.kube.Status().Update(ctx, cr); err != nil {
+		return fmt.Errorf("cannot claim %s job: %w", action, err)
+	}
+	job := recoveryPlanJob(cr, action)
+	jobUUID, err := ntxCli.RunRecoveryPlan(ctx, uuid, action)
+	if err != nil {
+		// Prism rejected the job, so the request is released to be retried.
+		if restore != nil {
+			*job = *restore
+		} else {
+			cr.Status.Jobs = removeRecoveryPlanJob(cr.Status.Jobs, action)
+		}
+		return fmt.Errorf("cannot start %s job: %w", action, err)
+	}
+	job.UUID, job.State = jobUUID, v1alpha1.RecoveryPlanJobRunning
+	return nil
+}
+
+func (e *recoveryPlanExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RecoveryPlan) error {
+	for _, job := range cr.Status.Jobs {
+		if job.State == v1alpha1.RecoveryPlanJobStarting || job.State == v1alpha1.RecoveryPlanJobRunning {
+			return fmt.Errorf("recovery plan %s is running a %s job", cr.Spec.Name, job.Action)
+		}
+	}
+	if err := ntxCli.DeleteRecoveryPlan(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// planInfo returns the Prism recovery plan described by cr, with its
+// datacenters resolved to availability zones.
+func (e *recoveryPlanExternal) planInfo(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RecoveryPlan) (nutanix.RecoveryPlanInfo, error) {
+	plan := nutanix.RecoveryPlanInfo{
+		Name:            cr.Spec.Name,
+		Description:     cr.Spec.Description,
+		Stages:          cr.Spec.Stages,
+		NetworkMappings: make([]v1alpha1.RecoveryPlanNetworkMapping, len(cr.Spec.NetworkMappings)),
+	}
+	for i, s := range cr.Spec.Stages {
+		for j, entity := range s.Entities {
+			if (len(entity.Categories) == 0) == (entity.VMName == "") {
+				return plan, fmt.Errorf("stage %d entity %d must set exactly one of categories and vmName", i, j)
+			}
+		}
+	}
+	for i, m := range cr.Spec.NetworkMappings {
+		if m.TestSubnetName == "" {
+			m.TestSubnetName = m.RecoverySubnetName
+		}
+		plan.NetworkMappings[i] = m
+	}
+
+	var pc v1beta1.ProviderConfig
+	if err := e.kube.Get(ctx, client.ObjectKey{Name: "default"}, &pc); err != nil {
+		return plan, err
+	}
+	azs := &availabilityZones{kube: e.kube, pc: &pc, limiter: e.limiter, local: ntxCli, datacenter: cr.Spec.Datacenter}
+	var err error
+	if plan.PrimaryAZURL, _, err = azs.resolve(ctx, v1alpha1.ProtectionLocation{Datacenter: cr.Spec.PrimaryDatacenter}); err != nil {
+		return plan, fmt.Errorf("cannot resolve primary datacenter: %w", err)
+	}
+	if plan.RecoveryAZURL, _, err = azs.resolve(ctx, v1alpha1.ProtectionLocation{Datacenter: cr.Spec.RecoveryDatacenter}); err != nil {
+		return plan, fmt.Errorf("cannot resolve recovery datacenter: %w", err)
+	}
+	if plan.PrimaryAZURL == plan.RecoveryAZURL {
+		return plan, fmt.Errorf("recovery datacenter must differ from the primary datacenter")
+	}
+	return plan, nil
+}
+
+// pendingRecoveryPlanJob returns the action and request of a job requested
+// through an annotation but not started yet. No job is pending while another
+// is starting or running.
+func pendingRecoveryPlanJob(cr *v1alpha1.RecoveryPlan) (action, request string) {
+	for _, job := range cr.Status.Jobs {
+		if job.State == v1alpha1.RecoveryPlanJobStarting || job.State == v1alpha1.RecoveryPlanJobRunning {
+			return "", ""
+		}
+	}
+	annotations := cr.GetAnnotations()
+	for _, a := range recoveryPlanActions {
+		request := annotations[a.annotation]
+		if request == "" {
+			continue
+		}
+		if job := recoveryPlanJob(cr, a.action); job != nil && job.Request == request {
+			continue
+		}
+		return a.action, request
+	}
+	return "", ""
+}
+
+// recoveryPlanJob returns the latest job of action, or nil if none was started.
+func recoveryPlanJob(cr *v1alpha1.RecoveryPlan, action string) *v1alpha1.RecoveryPlanJobStatus {
+	for i := range cr.Status.Jobs {
+		if cr.Status.Jobs[i].Action == action {
+			return &cr.Status.Jobs[i]
+		}
+	}
+	return nil
+}
+
+// setRecoveryPlanJob records job as the latest job of its action.
+func setRecoveryPlanJob(cr *v1alpha1.RecoveryPlan, job v1alpha1.RecoveryPlanJobStatus) {
+	if existing := recoveryPlanJob(cr, job.Action); existing != nil {
+		*existing = job
+		return
+	}
+	cr.Status.Jobs = append(cr.Status.Jobs, job)
+}
+
+// removeRecoveryPlanJob returns jobs without the job of action.
+func removeRecoveryPlanJob(jobs []v1alpha1.RecoveryPlanJobStatus, action string) []v1alpha1.RecoveryPlanJobStatus {
+	kept := jobs[:0]
+	for _, job := range jobs {
+		if job.Action != action {
+			kept = append(kept, job)
+		}
+	}
+	return kept
+}
+
+// refreshRecoveryPlanJobs updates the state of the plan's running jobs. A job
+// still starting was claimed by an earlier reconcile that did not record its
+// UUID; it may be running in Prism, so it is not started again.
+func refreshRecoveryPlanJobs(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RecoveryPlan) error {
+	for i := range cr.Status.Jobs {
+		job := &cr.Status.Jobs[i]
+		if job.State == v1alpha1.RecoveryPlanJobStarting {
+			job.State, job.Message = v1alpha1.RecoveryPlanJobUnknown, "job UUID was not recorded; check Prism before requesting the job again"
+			continue
+		}
+		if job.State != v1alpha1.RecoveryPlanJobRunning {
+			continue
+		}
+		info, err := ntxCli.GetRecoveryPlanJob(ctx, job.UUID)
+		if errors.Is(err, nutanix.ErrNotFound) {
+			job.State, job.Message = v1alpha1.RecoveryPlanJobFailed, "job no longer exists in Prism"
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cannot get %s job: %w", job.Action, err)
+		}
+		if info == nil {
+			continue
+		}
+		job.State, job.Message = info.State, info.Message
+		if info.StartTime != nil {
+			t := metav1.NewTime(*info.StartTime)
+			job.StartTime = &t
+		}
+		if info.EndTime != nil {
+			t := metav1.NewTime(*info.EndTime)
+			job.EndTime = &t
+		}
+	}
+	return nil
+}
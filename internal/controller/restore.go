@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// restoreVM creates the VM by restoring the recovery point selected by
+// spec.restoreFrom and returns the new VM's UUID. The restored VM keeps the
+// guest OS and credentials of the snapshotted VM; sizing and power state
+// differences are corrected by drift handling once it is observed.
+func (r *VirtualMachineReconciler) restoreVM(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "RestoreVM", tracing.AttrVMName.String(vm.Spec.Name))
+	defer func() { tracing.End(span, err) }()
+
+	rf := vm.Spec.RestoreFrom
+	rpUUID := rf.RecoveryPointUUID
+	switch {
+	case rf.SnapshotRef != nil && rpUUID != "":
+		return "", fmt.Errorf("restoreFrom must set only one of snapshotRef and recoveryPointUuid")
+	case rf.SnapshotRef != nil:
+		var snap v1alpha1.VirtualMachineSnapshot
+		if err := r.Get(ctx, client.ObjectKey{Namespace: vm.Namespace, Name: rf.SnapshotRef.Name}, &snap); err != nil {
+			return "", fmt.Errorf("cannot get VirtualMachineSnapshot %s: %w", rf.SnapshotRef.Name, err)
+		}
+		if snap.Spec.Datacenter != vm.Spec.Datacenter {
+			return "", fmt.Errorf("VirtualMachineSnapshot %s is in datacenter %q, not %q", snap.Name, snap.Spec.Datacenter, vm.Spec.Datacenter)
+		}
+		if snap.Status.State == v1alpha1.RecoveryPointStateExpired {
+			return "", fmt.Errorf("VirtualMachineSnapshot %s has expired", snap.Name)
+		}
+		rpUUID = meta.GetExternalName(&snap)
+		if rpUUID == "" {
+			return "", fmt.Errorf("VirtualMachineSnapshot %s has not been created yet", snap.Name)
+		}
+	case rpUUID == "":
+		return "", fmt.Errorf("restoreFrom must set snapshotRef or recoveryPointUuid")
+	}
+
+	id, err = ntxCli.RestoreVM(ctx, rpUUID, withOwnerCategory(vm))
+	if err != nil {
+		return "", err
+	}
+	r.record.Event(vm, event.Normal(reasonRestored, fmt.Sprintf("Restored VM %s from recovery point %s", vm.Spec.Name, rpUUID)))
+	return id, nil
+}
@@ -0,0 +1,346 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupSecurityPolicy adds a controller that reconciles SecurityPolicy resources.
+func SetupSecurityPolicy(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.SecurityPolicy](mgr, o, "SecurityPolicy",
+		func() *v1alpha1.SecurityPolicy { return &v1alpha1.SecurityPolicy{} }, &securityPolicyExternal{kube: mgr.GetClient()})
+}
+
+// SetupAddressGroup adds a controller that reconciles AddressGroup resources.
+func SetupAddressGroup(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.AddressGroup](mgr, o, "AddressGroup",
+		func() *v1alpha1.AddressGroup { return &v1alpha1.AddressGroup{} }, &addressGroupExternal{kube: mgr.GetClient()})
+}
+
+// SetupServiceGroup adds a controller that reconciles ServiceGroup resources.
+func SetupServiceGroup(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.ServiceGroup](mgr, o, "ServiceGroup",
+		func() *v1alpha1.ServiceGroup { return &v1alpha1.ServiceGroup{} }, &serviceGroupExternal{kube: mgr.GetClient()})
+}
+
+// securityPolicyExternal manages Flow security policies. The external name is
+// the policy UUID.
+type securityPolicyExternal struct {
+	kube client.Client
+}
+
+func (e *securityPolicyExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.SecurityPolicy) (externalObservation, error) {
+	observed, err := ntxCli.GetSecurityPolicy(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	desired, err := e.policyInfo(ctx, cr)
+	if err != nil {
+		return externalObservation{}, err
+	}
+	desired.UUID = observed.UUID
+	return externalObservation{Exists: true, UpToDate: reflect.DeepEqual(*observed, desired)}, nil
+}
+
+func (e *securityPolicyExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.SecurityPolicy) (string, error) {
+	if cr.Spec.Type == v1alpha1.SecurityPolicyQuarantine {
+		return "", fmt.Errorf("the quarantine policy cannot be created; import it by setting the %s annotation to its UUID", meta.AnnotationKeyExternalName)
+	}
+	policy, err := e.policyInfo(ctx, cr)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.PutSecurityPolicy(ctx, policy)
+}
+
+func (e *securityPolicyExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.SecurityPolicy) error {
+	policy, err := e.policyInfo(ctx, cr)
+	if err != nil {
+		return err
+	}
+	policy.UUID = meta.GetExternalName(cr)
+	_, err = ntxCli.PutSecurityPolicy(ctx, policy)
+	return err
+}
+
+func (e *securityPolicyExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.SecurityPolicy) error {
+	if cr.Spec.Type == v1alpha1.SecurityPolicyQuarantine {
+		return nil
+	}
+	if err := ntxCli.DeleteSecurityPolicy(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// policyInfo validates cr against its type and returns the Flow security
+// policy it describes, with address and service groups resolved to UUIDs.
+func (e *securityPolicyExternal) policyInfo(ctx context.Context, cr *v1alpha1.SecurityPolicy) (nutanix.SecurityPolicyInfo, error) {
+	spec := cr.Spec
+	policy := nutanix.SecurityPolicyInfo{
+		Name:             spec.Name,
+		Description:      spec.Description,
+		Type:             spec.Type,
+		Mode:             spec.Mode,
+		AllowAllInbound:  spec.AllowAllInbound,
+		AllowAllOutbound: spec.AllowAllOutbound,
+	}
+	if policy.Mode == "" {
+		policy.Mode = v1alpha1.SecurityPolicyModeMonitor
+	}
+	switch spec.Type {
+	case v1alpha1.SecurityPolicyApplication:
+		if len(spec.AppliedTo) == 0 {
+			return policy, fmt.Errorf("appliedTo is required for APPLICATION policies")
+		}
+		if spec.Isolation != nil {
+			return policy, fmt.Errorf("isolation is only valid for ISOLATION policies")
+		}
+		policy.AppliedTo = spec.AppliedTo
+	case v1alpha1.SecurityPolicyIsolation:
+		if spec.Isolation == nil {
+			return policy, fmt.Errorf("isolation is required for ISOLATION policies")
+		}
+		if len(spec.AppliedTo) > 0 || len(spec.Inbound) > 0 || len(spec.Outbound) > 0 || spec.AllowAllInbound || spec.AllowAllOutbound {
+			return policy, fmt.Errorf("ISOLATION policies only support isolation")
+		}
+		policy.Isolation = spec.Isolation
+		return policy, nil
+	case v1alpha1.SecurityPolicyQuarantine:
+		if len(spec.AppliedTo) > 0 || spec.Isolation != nil {
+			return policy, fmt.Errorf("QUARANTINE policies apply to quarantined VMs and do not support appliedTo or isolation")
+		}
+	}
+	if spec.AllowAllInbound && len(spec.Inbound) > 0 {
+		return policy, fmt.Errorf("inbound must be empty when allowAllInbound is set")
+	}
+	if spec.AllowAllOutbound && len(spec.Outbound) > 0 {
+		return policy, fmt.Errorf("outbound must be empty when allowAllOutbound is set")
+	}
+
+	var err error
+	if policy.Inbound, err = e.rules(ctx, "inbound", spec.Inbound); err != nil {
+		return policy, err
+	}
+	if policy.Outbound, err = e.rules(ctx, "outbound", spec.Outbound); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// rules returns the Flow allow list entries of rules.
+func (e *securityPolicyExternal) rules(ctx context.Context, direction string, rules []v1alpha1.SecurityRule) ([]nutanix.SecurityRuleInfo, error) {
+	var out []nutanix.SecurityRuleInfo
+	for i, r := range rules {
+		peers := 0
+		for _, set := range []bool{len(r.Categories) > 0, r.AddressGroupRef != nil, r.CIDR != ""} {
+			if set {
+				peers++
+			}
+		}
+		if peers != 1 {
+			return nil, fmt.Errorf("%s rule %d must set exactly one of categories, addressGroupRef and cidr", direction, i)
+		}
+		info := nutanix.SecurityRuleInfo{
+			Description: r.Description,
+			Categories:  r.Categories,
+			CIDR:        r.CIDR,
+			Services:    normalizeServices(r.Services),
+		}
+		if r.AddressGroupRef != nil {
+			var ag v1alpha1.AddressGroup
+			uuid, err := externalNameOf(ctx, e.kube, "AddressGroup", r.AddressGroupRef.Name, &ag)
+			if err != nil {
+				return nil, err
+			}
+			info.AddressGroupUUID = uuid
+		}
+		for _, ref := range r.ServiceGroupRefs {
+			var sg v1alpha1.ServiceGroup
+			uuid, err := externalNameOf(ctx, e.kube, "ServiceGroup", ref.Name, &sg)
+			if err != nil {
+				return nil, err
+			}
+			info.ServiceGroupUUIDs = append(info.ServiceGroupUUIDs, uuid)
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+// externalNameOf gets the cluster-scoped resource of kind named name into
+// obj and returns its external name, failing if it has not been created yet.
+func externalNameOf(ctx context.Context, kube client.Client, kind, name string, obj client.Object) (string, error) {
+	if err := kube.Get(ctx, client.ObjectKey{Name: name}, obj); err != nil {
+		return "", fmt.Errorf("cannot get %s %s: %w", kind, name, err)
+	}
+	uuid := meta.GetExternalName(obj)
+	if uuid == "" {
+		return "", fmt.Errorf("%s %s has not been created yet", kind, name)
+	}
+	return uuid, nil
+}
+
+// normalizeServices defaults the end of port ranges to their start.
+func normalizeServices(services []v1alpha1.NetworkService) []v1alpha1.NetworkService {
+	if len(services) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.NetworkService, len(services))
+	for i, s := range services {
+		if len(s.PortRanges) > 0 {
+			ranges := make([]v1alpha1.PortRange, len(s.PortRanges))
+			for j, r := range s.PortRanges {
+				if r.End == 0 {
+					r.End = r.Start
+				}
+				ranges[j] = r
+			}
+			s.PortRanges = ranges
+		}
+		out[i] = s
+	}
+	return out
+}
+
+// addressGroupExternal manages Flow address groups. The external name is the
+// group UUID.
+type addressGroupExternal struct {
+	kube client.Client
+}
+
+func (e *addressGroupExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.AddressGroup) (externalObservation, error) {
+	observed, err := ntxCli.GetAddressGroup(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	upToDate := observed.Name == cr.Spec.Name &&
+		observed.Description == cr.Spec.Description &&
+		reflect.DeepEqual(sortedStrings(observed.CIDRs), sortedStrings(cr.Spec.CIDRs))
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *addressGroupExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.AddressGroup) (string, error) {
+	return ntxCli.PutAddressGroup(ctx, nutanix.AddressGroupInfo{Name: cr.Spec.Name, Description: cr.Spec.Description, CIDRs: cr.Spec.CIDRs})
+}
+
+func (e *addressGroupExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.AddressGroup) error {
+	_, err := ntxCli.PutAddressGroup(ctx, nutanix.AddressGroupInfo{
+		UUID: meta.GetExternalName(cr), Name: cr.Spec.Name, Description: cr.Spec.Description, CIDRs: cr.Spec.CIDRs,
+	})
+	return err
+}
+
+func (e *addressGroupExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.AddressGroup) error {
+	user, err := securityPolicyUsing(ctx, e.kube, func(r v1alpha1.SecurityRule) bool {
+		return r.AddressGroupRef != nil && r.AddressGroupRef.Name == cr.Name
+	})
+	if err != nil {
+		return err
+	}
+	if user != "" {
+		return fmt.Errorf("address group %s is still used by SecurityPolicy %s", cr.Spec.Name, user)
+	}
+	if err := ntxCli.DeleteAddressGroup(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// serviceGroupExternal manages Flow service groups. The external name is the
+// group UUID.
+type serviceGroupExternal struct {
+	kube client.Client
+}
+
+func (e *serviceGroupExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ServiceGroup) (externalObservation, error) {
+	observed, err := ntxCli.GetServiceGroup(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	upToDate := observed.Name == cr.Spec.Name &&
+		observed.Description == cr.Spec.Description &&
+		reflect.DeepEqual(observed.Services, normalizeServices(cr.Spec.Services))
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *serviceGroupExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ServiceGroup) (string, error) {
+	return ntxCli.PutServiceGroup(ctx, nutanix.ServiceGroupInfo{
+		Name: cr.Spec.Name, Description: cr.Spec.Description, Services: normalizeServices(cr.Spec.Services),
+	})
+}
+
+func (e *serviceGroupExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ServiceGroup) error {
+	_, err := ntxCli.PutServiceGroup(ctx, nutanix.ServiceGroupInfo{
+		UUID: meta.GetExternalName(cr), Name: cr.Spec.Name, Description: cr.Spec.Description, Services: normalizeServices(cr.Spec.Services),
+	})
+	return err
+}
+
+func (e *serviceGroupExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.ServiceGroup) error {
+	user, err := securityPolicyUsing(ctx, e.kube, func(r v1alpha1.SecurityRule) bool {
+		for _, ref := range r.ServiceGroupRefs {
+			if ref.Name == cr.Name {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	if user != "" {
+		return fmt.Errorf("service group %s is still used by SecurityPolicy %s", cr.Spec.Name, user)
+	}
+	if err := ntxCli.DeleteServiceGroup(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// securityPolicyUsing returns the name of the first SecurityPolicy with a
+// rule for which uses returns true, or "" if there is none.
+func securityPolicyUsing(ctx context.Context, kube client.Client, uses func(v1alpha1.SecurityRule) bool) (string, error) {
+	var policies v1alpha1.SecurityPolicyList
+	if err := kube.List(ctx, &policies); err != nil {
+		return "", fmt.Errorf("cannot list SecurityPolicies: %w", err)
+	}
+	for _, p := range policies.Items {
+		for _, r := range append(append([]v1alpha1.SecurityRule(nil), p.Spec.Inbound...), p.Spec.Outbound...) {
+			if uses(r) {
+				return p.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
@@ -1,13 +1,46 @@
 package controller
 
 import (
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	xpcontroller "github.com/crossplane/crossplane-runtime/pkg/controller"
+	"golang.org/x/time/rate"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
-func Setup(mgr manager.Manager, l logging.Logger) error {
-	if err := SetupVirtualMachine(mgr, l); err != nil {
-		return err
+// Options configures the Nutanix controllers.
+type Options struct {
+	xpcontroller.Options
+
+	// PrismRateLimiter bounds the rate of Prism API calls made by all
+	// controllers. A nil limiter does not limit calls.
+	PrismRateLimiter *rate.Limiter
+}
+
+func Setup(mgr manager.Manager, o Options) error {
+	for _, setup := range []func(manager.Manager, Options) error{
+		SetupVirtualMachine,
+		SetupCategory,
+		SetupCategoryValue,
+		SetupImage,
+		SetupSubnet,
+		SetupProject,
+		SetupVolumeGroup,
+		SetupVirtualMachineSnapshot,
+		SetupProtectionPolicy,
+		SetupRecoveryPlan,
+		SetupSecurityPolicy,
+		SetupAddressGroup,
+		SetupServiceGroup,
+		SetupVPC,
+		SetupFloatingIP,
+		SetupRoutingPolicy,
+		SetupVPNGateway,
+		SetupStorageContainer,
+		SetupHostAffinityPolicy,
+		SetupVMTemplate,
+	} {
+		if err := setup(mgr, o); err != nil {
+			return err
+		}
 	}
 	return nil
 }
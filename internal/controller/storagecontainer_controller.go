@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupStorageContainer adds a controller that reconciles StorageContainer resources.
+func SetupStorageContainer(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.StorageContainer](mgr, o, "StorageContainer",
+		func() *v1alpha1.StorageContainer { return &v1alpha1.StorageContainer{} }, &storageContainerExternal{kube: mgr.GetClient()})
+}
+
+// storageContainerExternal manages Prism storage containers. The external
+// name is the container UUID.
+type storageContainerExternal struct {
+	kube client.Client
+}
+
+func (e *storageContainerExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.StorageContainer) (externalObservation, error) {
+	observed, err := ntxCli.GetStorageContainer(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.UsedBytes = observed.UsedBytes
+	cr.Status.CapacityBytes = observed.CapacityBytes
+	if err := validateStorageContainer(cr.Spec); err != nil {
+		return externalObservation{}, err
+	}
+
+	spec := cr.Spec
+	upToDate := observed.Name == spec.Name &&
+		(spec.ReplicationFactor == 0 || observed.ReplicationFactor == spec.ReplicationFactor) &&
+		observed.Compression == (spec.Compression != nil) &&
+		(spec.Compression == nil || observed.CompressionDelaySeconds == spec.Compression.DelaySeconds) &&
+		observed.Deduplication == spec.Deduplication &&
+		observed.ErasureCoding == spec.ErasureCoding &&
+		observed.AdvertisedCapacityGb == spec.AdvertisedCapacityGb &&
+		observed.ReservedCapacityGb == spec.ReservedCapacityGb
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *storageContainerExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.StorageContainer) (string, error) {
+	if err := validateStorageContainer(cr.Spec); err != nil {
+		return "", err
+	}
+	clusterUUID, err := fetchClusterUUID(ctx, ntxCli, cr.Spec.ClusterName)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.CreateStorageContainer(ctx, cr.Spec, clusterUUID)
+}
+
+func (e *storageContainerExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.StorageContainer) error {
+	if err := validateStorageContainer(cr.Spec); err != nil {
+		return err
+	}
+	return ntxCli.UpdateStorageContainer(ctx, meta.GetExternalName(cr), cr.Spec)
+}
+
+func (e *storageContainerExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.StorageContainer) error {
+	var vms v1alpha1.VirtualMachineList
+	if err := e.kube.List(ctx, &vms); err != nil {
+		return fmt.Errorf("cannot list VirtualMachines: %w", err)
+	}
+	for _, vm := range vms.Items {
+		if b := vm.Spec.BootDisk; b != nil && b.StorageContainerRef != nil && b.StorageContainerRef.Name == cr.Name {
+			return fmt.Errorf("storage container %s is still used by VirtualMachine %s/%s", cr.Spec.Name, vm.Namespace, vm.Name)
+		}
+		for _, d := range vm.Spec.AdditionalDisks {
+			if d.StorageContainerRef != nil && d.StorageContainerRef.Name == cr.Name {
+				return fmt.Errorf("storage container %s is still used by VirtualMachine %s/%s", cr.Spec.Name, vm.Namespace, vm.Name)
+			}
+		}
+	}
+	if err := ntxCli.DeleteStorageContainer(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// validateStorageContainer checks the settings of spec against each other.
+func validateStorageContainer(spec v1alpha1.StorageContainerSpec) error {
+	if spec.ErasureCoding && spec.ReplicationFactor == 1 {
+		return fmt.Errorf("erasureCoding requires a replicationFactor of at least 2")
+	}
+	if spec.ReservedCapacityGb > 0 && spec.AdvertisedCapacityGb > 0 && spec.ReservedCapacityGb > spec.AdvertisedCapacityGb {
+		return fmt.Errorf("reservedCapacityGb %d exceeds advertisedCapacityGb %d", spec.ReservedCapacityGb, spec.AdvertisedCapacityGb)
+	}
+	return nil
+}
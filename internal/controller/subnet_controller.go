@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupSubnet adds a controller that reconciles Subnet resources.
+func SetupSubnet(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.Subnet](mgr, o, "Subnet",
+		func() *v1alpha1.Subnet { return &v1alpha1.Subnet{} }, &subnetExternal{kube: mgr.GetClient()})
+}
+
+// subnetExternal manages Prism subnets. The external name is the subnet UUID.
+type subnetExternal struct {
+	kube client.Client
+}
+
+func (e *subnetExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Subnet) (externalObservation, error) {
+	observed, err := ntxCli.GetSubnet(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	published, err := e.networkProfileUpToDate(ctx, cr)
+	if err != nil {
+		return externalObservation{}, err
+	}
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: published}, nil
+	}
+	cr.Status.FreeIPs = observed.FreeIPs
+	upToDate := published &&
+		observed.Name == cr.Spec.Name &&
+		observed.Description == cr.Spec.Description &&
+		reflect.DeepEqual(observed.IPConfig, cr.Spec.IPConfig) &&
+		formatCategories(observed.Categories) == formatCategories(cr.Spec.Categories)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *subnetExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Subnet) (string, error) {
+	var clusterUUID string
+	switch cr.Spec.SubnetType {
+	case v1alpha1.SubnetTypeOverlay:
+		if cr.Spec.External {
+			return "", fmt.Errorf("only VLAN subnets can be external")
+		}
+		if cr.Spec.VPCRef != nil {
+			var vpc v1alpha1.VPC
+			uuid, err := externalNameOf(ctx, e.kube, "VPC", cr.Spec.VPCRef.Name, &vpc)
+			if err != nil {
+				return "", err
+			}
+			if cr.Spec.VPCUUID != "" && cr.Spec.VPCUUID != uuid {
+				return "", fmt.Errorf("vpcUuid %s does not match VPC %s", cr.Spec.VPCUUID, cr.Spec.VPCRef.Name)
+			}
+			spec := cr.Spec
+			spec.VPCUUID = uuid
+			return ntxCli.CreateSubnet(ctx, spec, "")
+		}
+		if cr.Spec.VPCUUID == "" {
+			return "", fmt.Errorf("vpcUuid or vpcRef is required for OVERLAY subnets")
+		}
+	default:
+		if cr.Spec.ClusterName == "" {
+			return "", fmt.Errorf("clusterName is required for VLAN subnets")
+		}
+		uuid, err := fetchClusterUUID(ctx, ntxCli, cr.Spec.ClusterName)
+		if err != nil {
+			return "", err
+		}
+		clusterUUID = uuid
+	}
+	return ntxCli.CreateSubnet(ctx, cr.Spec, clusterUUID)
+}
+
+// Update also publishes the subnet's network details, which Observe reports
+// as out of date until then, including right after the subnet is created.
+func (e *subnetExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Subnet) error {
+	if err := ntxCli.UpdateSubnet(ctx, meta.GetExternalName(cr), cr.Spec); err != nil {
+		return err
+	}
+	return e.publishNetworkProfile(ctx, cr)
+}
+
+func (e *subnetExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.Subnet) error {
+	if err := ntxCli.DeleteSubnet(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	if err := e.unpublishNetworkProfile(ctx, cr.Status.NetworkProfile); err != nil {
+		return err
+	}
+	cr.Status.NetworkProfile = nil
+	return nil
+}
+
+// networkProfileKey is the ConfigMap key holding a subnet's network details,
+// matching the file name read by readDetailsByName.
+func networkProfileKey(cr *v1alpha1.Subnet) string {
+	return "network-" + cr.Spec.Name + ".json"
+}
+
+// networkProfile returns the subnet's network details in the format of the
+// network JSON files read by the VirtualMachine controller.
+func networkProfile(cr *v1alpha1.Subnet) map[string]interface{} {
+	np := cr.Spec.NetworkProfile
+	details := map[string]interface{}{}
+	for k, v := range np.Extra {
+		details[k] = v
+	}
+	details["subnet"] = cr.Spec.Name
+	if ip := cr.Spec.IPConfig; ip != nil {
+		details["network"] = ip.NetworkCIDR
+		if ip.DefaultGateway != "" {
+			details["gateway"] = ip.DefaultGateway
+		}
+		if dhcp := ip.DHCPOptions; dhcp != nil {
+			if len(dhcp.DNSServers) > 0 {
+				details["nameserver"] = dhcp.DNSServers[0]
+			}
+			if dhcp.DomainName != "" {
+				details["domain"] = dhcp.DomainName
+			}
+		}
+	}
+	repos := np.AllowedRepos
+	if repos == nil {
+		repos = []string{}
+	}
+	details["allowed_repos"] = repos
+	return details
+}
+
+// desiredNetworkProfile returns the ConfigMap entry the subnet's network
+// details are published to, or nil if they are not published.
+func desiredNetworkProfile(cr *v1alpha1.Subnet) *v1alpha1.PublishedNetworkProfile {
+	np := cr.Spec.NetworkProfile
+	if np == nil {
+		return nil
+	}
+	return &v1alpha1.PublishedNetworkProfile{ConfigMapRef: np.ConfigMapRef, Key: networkProfileKey(cr)}
+}
+
+// networkProfileUpToDate reports whether the subnet's network details are
+// published where and as its spec requires, and nowhere else.
+func (e *subnetExternal) networkProfileUpToDate(ctx context.Context, cr *v1alpha1.Subnet) (bool, error) {
+	desired, published := desiredNetworkProfile(cr), cr.Status.NetworkProfile
+	if desired == nil || published == nil || *desired != *published {
+		return desired == nil && published == nil, nil
+	}
+	data, err := json.MarshalIndent(networkProfile(cr), "", "    ")
+	if err != nil {
+		return false, err
+	}
+	var cm corev1.ConfigMap
+	err = e.kube.Get(ctx, client.ObjectKey{Namespace: desired.ConfigMapRef.Namespace, Name: desired.ConfigMapRef.Name}, &cm)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot get network profile ConfigMap: %w", err)
+	}
+	return cm.Data[desired.Key] == string(data), nil
+}
+
+// publishNetworkProfile writes the subnet's network details to the ConfigMap
+// named by its network profile, after removing them from where they were
+// previously published, e.g. under the subnet's old name.
+func (e *subnetExternal) publishNetworkProfile(ctx context.Context, cr *v1alpha1.Subnet) error {
+	desired := desiredNetworkProfile(cr)
+	if published := cr.Status.NetworkProfile; published != nil && (desired == nil || *published != *desired) {
+		if err := e.unpublishNetworkProfile(ctx, published); err != nil {
+			return err
+		}
+		cr.Status.NetworkProfile = nil
+	}
+	if desired == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(networkProfile(cr), "", "    ")
+	if err != nil {
+		return err
+	}
+
+	var cm corev1.ConfigMap
+	key := client.ObjectKey{Namespace: desired.ConfigMapRef.Namespace, Name: desired.ConfigMapRef.Name}
+	err = e.kube.Get(ctx, key, &cm)
+	switch {
+	case kerrors.IsNotFound(err):
+		cm = corev1.ConfigMap{}
+		cm.Namespace, cm.Name = key.Namespace, key.Name
+		cm.Data = map[string]string{desired.Key: string(data)}
+		if err := e.kube.Create(ctx, &cm); err != nil {
+			return fmt.Errorf("cannot create network profile ConfigMap: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("cannot get network profile ConfigMap: %w", err)
+	case cm.Data[desired.Key] != string(data):
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[desired.Key] = string(data)
+		if err := e.kube.Update(ctx, &cm); err != nil {
+			return fmt.Errorf("cannot update network profile ConfigMap: %w", err)
+		}
+	}
+	cr.Status.NetworkProfile = desired
+	return nil
+}
+
+// unpublishNetworkProfile removes the network details published to the
+// ConfigMap entry p, if any.
+func (e *subnetExternal) unpublishNetworkProfile(ctx context.Context, p *v1alpha1.PublishedNetworkProfile) error {
+	if p == nil {
+		return nil
+	}
+	var cm corev1.ConfigMap
+	if err := e.kube.Get(ctx, client.ObjectKey{Namespace: p.ConfigMapRef.Namespace, Name: p.ConfigMapRef.Name}, &cm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if _, ok := cm.Data[p.Key]; !ok {
+		return nil
+	}
+	delete(cm.Data, p.Key)
+	if err := e.kube.Update(ctx, &cm); err != nil {
+		return fmt.Errorf("cannot update network profile ConfigMap: %w", err)
+	}
+	return nil
+}
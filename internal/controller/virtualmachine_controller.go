@@ -4,26 +4,48 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
 	"github.com/mgeorge67701/provider-nutanix/apis/v1beta1"
+	"github.com/mgeorge67701/provider-nutanix/internal/features"
 	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// mappingHTTPClient fetches the availability zone mapping CSV with trace context propagated.
+var mappingHTTPClient = &http.Client{Transport: tracing.Transport(nil)}
+
 // Fetches the mapping from a CSV URL and returns a map[AvailabilityZone]ClusterName for only enabled zones
 // Also returns a set of all zones and a map of zone->enabled for error reporting
-func fetchAvailabilityZoneMapping(url string) (map[string]string, map[string]bool, error) {
-	resp, err := http.Get(url)
+func fetchAvailabilityZoneMapping(ctx context.Context, url string) (map[string]string, map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := mappingHTTPClient.Do(req)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -87,14 +109,61 @@ func fetchAvailabilityZoneMapping(url string) (map[string]string, map[string]boo
 
 // ...existing code...
 
+// Event reasons recorded against a VirtualMachine.
+const (
+	reasonResolvedImage   event.Reason = "ResolvedImage"
+	reasonPlacedOnCluster event.Reason = "PlacedOnCluster"
+	reasonCreateStarted   event.Reason = "CreateStarted"
+	reasonCreateSucceeded event.Reason = "CreateSucceeded"
+	reasonPolicyRejected  event.Reason = "PolicyRejected"
+	reasonCannotConnect   event.Reason = "CannotConnectToPrism"
+	reasonCannotPlace     event.Reason = "CannotPlace"
+	reasonCannotCreate    event.Reason = "CannotCreate"
+	reasonCannotObserve   event.Reason = "CannotObserve"
+	reasonCannotDelete    event.Reason = "CannotDelete"
+
+	reasonDriftDetected      event.Reason = "DriftDetected"
+	reasonDriftCorrected     event.Reason = "DriftCorrected"
+	reasonCannotCorrectDrift event.Reason = "CannotCorrectDrift"
+
+	reasonAdopted     event.Reason = "Adopted"
+	reasonCannotAdopt event.Reason = "CannotAdopt"
+
+	reasonCannotPublishConnection event.Reason = "CannotPublishConnectionDetails"
+
+	reasonGuestReady    event.Reason = "GuestReady"
+	reasonGuestNotReady event.Reason = "GuestNotReady"
+
+	reasonGuestToolsUpdated      event.Reason = "GuestToolsUpdated"
+	reasonCannotManageGuestTools event.Reason = "CannotManageGuestTools"
+
+	reasonCreatedCategoryValue event.Reason = "CreatedCategoryValue"
+
+	reasonRestored event.Reason = "Restored"
+	reasonCloned   event.Reason = "Cloned"
+
+	reasonVolumeGroupsUpdated      event.Reason = "VolumeGroupsUpdated"
+	reasonCannotAttachVolumeGroups event.Reason = "CannotAttachVolumeGroups"
+
+	reasonFloatingIPUpdated      event.Reason = "FloatingIPUpdated"
+	reasonCannotAssignFloatingIP event.Reason = "CannotAssignFloatingIP"
+
+	reasonAntiAffinityUpdated event.Reason = "AntiAffinityGroupUpdated"
+)
+
 type VirtualMachineReconciler struct {
 	client.Client
-	log logging.Logger
+	log          logging.Logger
+	record       event.Recorder
+	publisher    managed.ConnectionPublisher
+	store        *connection.DetailsManager
+	pollInterval time.Duration
+	prismLimiter *rate.Limiter
 }
 
 // Function to fetch cluster UUID dynamically from Nutanix
-func fetchClusterUUID(ntxCli *nutanix.Client, clusterName string) (string, error) {
-	clusters, err := ntxCli.ListClusters()
+func fetchClusterUUID(ctx context.Context, ntxCli *nutanix.Client, clusterName string) (string, error) {
+	clusters, err := ntxCli.ListClusters(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -131,52 +200,411 @@ func getValue(details map[string]interface{}, key string) (string, error) {
 	return "", fmt.Errorf("key '%s' not found in details", key)
 }
 
-func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) {
 	r.log.Debug("Reconciling Nutanix VirtualMachine", "name", req.NamespacedName)
 
+	ctx, span := tracing.Start(ctx, "VirtualMachineReconciler.Reconcile",
+		attribute.String("k8s.namespace", req.Namespace), attribute.String("k8s.name", req.Name))
+	defer func() { tracing.End(span, err) }()
+
 	var vm v1alpha1.VirtualMachine
 	if err := r.Get(ctx, req.NamespacedName, &vm); err != nil {
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
+	span.SetAttributes(tracing.AttrVMName.String(vm.Spec.Name), tracing.AttrVMUUID.String(vm.Status.VMID))
 
 	// Load ProviderConfig
 	var pc v1beta1.ProviderConfig
 	// Assuming the provider config is named "default", adjust if necessary
 	if err := r.Get(ctx, client.ObjectKey{Name: "default"}, &pc); err != nil {
-		return reconcile.Result{}, err
+		return r.fail(ctx, &vm, reasonCannotConnect, err)
+	}
+
+	if meta.WasDeleted(&vm) {
+		// Deletion skips validation, so that a VM the policy no longer
+		// allows can still be removed.
+		vm.SetConditions(xpv1.Deleting())
+		if meta.FinalizerExists(&vm, finalizerName) {
+			ntxCli, err := connectPrism(ctx, r.Client, &pc, vm.Spec.Datacenter, r.prismLimiter)
+			if err != nil {
+				return r.fail(ctx, &vm, reasonCannotConnect, err)
+			}
+			if err := r.releaseFloatingIP(ctx, ntxCli, &vm); err != nil {
+				return r.fail(ctx, &vm, reasonCannotDelete, err)
+			}
+			if err := r.releaseAntiAffinityGroup(ctx, ntxCli, &vm); err != nil {
+				return r.fail(ctx, &vm, reasonCannotDelete, err)
+			}
+			if vm.Status.VMID != "" {
+				if err := ntxCli.DeleteVM(ctx, vm.Status.VMID); err != nil {
+					r.log.Debug("Failed to delete VM", "error", err)
+					return r.fail(ctx, &vm, reasonCannotDelete, err)
+				}
+			}
+			if err := r.publisher.UnpublishConnection(ctx, &vm, managed.ConnectionDetails{}); err != nil {
+				return r.fail(ctx, &vm, reasonCannotPublishConnection, err)
+			}
+		}
+		meta.RemoveFinalizer(&vm, finalizerName)
+		return reconcile.Result{}, r.Update(ctx, &vm)
+	}
+	// The finalizer is added before validation mutates the in-memory spec,
+	// as Update writes the whole object.
+	if !meta.FinalizerExists(&vm, finalizerName) {
+		meta.AddFinalizer(&vm, finalizerName)
+		if err := r.Update(ctx, &vm); err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
-	// If availabilityZone is specified and mapping is enabled, fetch mapping from ProviderConfig's URL and map it to clusterName
-	if vm.Spec.AvailabilityZone != "" && pc.Spec.EnableAvailabilityZoneMapping {
-		mappingURL := pc.Spec.AvailabilityZoneMappingURL
-		if mappingURL == "" {
-			return reconcile.Result{}, fmt.Errorf("availabilityZone specified but ProviderConfig does not have availabilityZoneMappingURL set")
+	if err := validateLoB(&pc, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+	if err := validateDatacenter(&pc, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+	if err := r.selectProject(ctx, &pc, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+	if err := validateCPU(&vm); err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+	if err := validateSource(&vm); err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+	if err := validateGuestTools(&vm); err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+	if err := r.validateConnection(&vm); err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+	vm.SetConditions(v1alpha1.Validated())
+
+	ntxCli, err := connectPrism(ctx, r.Client, &pc, vm.Spec.Datacenter, r.prismLimiter)
+	if err != nil {
+		return r.fail(ctx, &vm, reasonCannotConnect, err)
+	}
+
+	if vm.Status.VMID == "" {
+		if externalName := meta.GetExternalName(&vm); externalName != "" {
+			if err := r.adopt(ctx, ntxCli, &vm, externalName); err != nil {
+				r.log.Debug("Failed to adopt VM", "externalName", externalName, "error", err)
+				return r.fail(ctx, &vm, reasonCannotAdopt, err)
+			}
+		}
+	}
+
+	protection, err := r.applyProtection(ctx, &vm)
+	if err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+	if err := r.applyCategories(ctx, ntxCli, &pc, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonPolicyRejected, err, v1alpha1.ValidationFailed(err))
+	}
+
+	if err := r.mapAvailabilityZone(ctx, &pc, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPlace, err, v1alpha1.PlacementFailed(err))
+	}
+	if err := r.resolveCluster(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPlace, err, v1alpha1.PlacementFailed(err))
+	}
+	if err := r.resolveImages(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPlace, err, v1alpha1.PlacementFailed(err))
+	}
+	if err := r.resolveDisks(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPlace, err, v1alpha1.PlacementFailed(err))
+	}
+	if err := validateBoot(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPlace, err, v1alpha1.PlacementFailed(err))
+	}
+	if err := r.resolveSubnet(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPlace, err, v1alpha1.PlacementFailed(err))
+	}
+	if err := r.resolveProject(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPlace, err, v1alpha1.PlacementFailed(err))
+	}
+	if err := r.applyAntiAffinityGroup(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPlace, err, v1alpha1.PlacementFailed(err))
+	}
+	if vm.GetCondition(v1alpha1.TypePlaced).Reason != v1alpha1.ReasonPlaced {
+		r.record.Event(&vm, event.Normal(reasonPlacedOnCluster, fmt.Sprintf("Placed on cluster %s (%s)", vm.Spec.ClusterName, vm.Spec.ClusterUUID)))
+	}
+	vm.SetConditions(v1alpha1.Placed(vm.Spec.ClusterName))
+
+	if vm.Status.VMID == "" {
+		// Handle create. A create that was started but never recorded as
+		// succeeded or failed may have created the VM, so it is not retried
+		// blindly.
+		if meta.ExternalCreateIncomplete(&vm) {
+			return r.fail(ctx, &vm, reasonCannotCreate, errors.New(errCreateIncomplete))
+		}
+		r.record.Event(&vm, event.Normal(reasonCreateStarted, fmt.Sprintf("Creating VM %s", vm.Spec.Name)))
+		if err := ntxCli.EnsureCategoryValue(ctx, ownerCategoryKey, string(vm.GetUID())); err != nil {
+			return r.fail(ctx, &vm, reasonCannotCreate, err)
+		}
+		var conn managed.ConnectionDetails
+		if vm.Spec.RestoreFrom == nil {
+			conn, err = r.publishConnection(ctx, &vm, nil)
+			if err != nil {
+				return r.fail(ctx, &vm, reasonCannotPublishConnection, err)
+			}
+		}
+		meta.SetExternalCreatePending(&vm, time.Now())
+		if err := r.updateCriticalAnnotations(ctx, &vm); err != nil {
+			return r.fail(ctx, &vm, reasonCannotCreate, fmt.Errorf("cannot record pending create: %w", err))
+		}
+		var id string
+		switch {
+		case vm.Spec.RestoreFrom != nil:
+			id, err = r.restoreVM(ctx, ntxCli, &vm)
+		case vm.Spec.Source != nil:
+			id, err = r.cloneVM(ctx, ntxCli, &vm, guestCustomization(&vm, conn))
+		default:
+			id, err = ntxCli.CreateVM(ctx, withOwnerCategory(&vm), guestCustomization(&vm, conn))
 		}
-		mapping, enabledMap, err := fetchAvailabilityZoneMapping(mappingURL)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("failed to fetch availability zone mapping: %v", err)
+			r.log.Debug("Failed to create VM", "error", err)
+			meta.SetExternalCreateFailed(&vm, time.Now())
+			if uerr := r.updateCriticalAnnotations(ctx, &vm); uerr != nil {
+				r.log.Debug("Failed to record failed VM create", "error", uerr)
+			}
+			return r.fail(ctx, &vm, reasonCannotCreate, err)
+		}
+		span.SetAttributes(tracing.AttrVMUUID.String(id))
+		r.record.Event(&vm, event.Normal(reasonCreateSucceeded, fmt.Sprintf("Created VM %s with UUID %s", vm.Spec.Name, id)))
+		// The VM is re-adopted through its external name if the status
+		// update below is lost. Should recording it fail, the pending create
+		// annotation keeps the VM from being created again.
+		meta.SetExternalName(&vm, id)
+		meta.SetExternalCreateSucceeded(&vm, time.Now())
+		if err := r.updateCriticalAnnotations(ctx, &vm); err != nil {
+			return r.fail(ctx, &vm, reasonCannotCreate, fmt.Errorf("cannot record external name %s: %w", id, err))
 		}
-		enabled, found := enabledMap[vm.Spec.AvailabilityZone]
-		if !found {
-			allowed := make([]string, 0, len(enabledMap))
-			for k := range enabledMap {
-				allowed = append(allowed, k)
+		vm.Status.VMID = id
+		vm.Status.State = "Created"
+		now := metav1.Now()
+		vm.Status.CreationTime = &now
+		vm.SetConditions(xpv1.Creating(), v1alpha1.GuestUnknown(), xpv1.ReconcileSuccess())
+		if err := r.Status().Update(ctx, &vm); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+	}
+
+	// Handle observe
+	observed, err := ntxCli.GetVM(ctx, vm.Status.VMID)
+	if err != nil {
+		r.log.Debug("Failed to get VM", "error", err)
+		return r.fail(ctx, &vm, reasonCannotObserve, err)
+	}
+	if err := r.setExternalName(ctx, &vm, vm.Status.VMID); err != nil {
+		return r.fail(ctx, &vm, reasonCannotObserve, err)
+	}
+	if observed != nil {
+		vm.Status.Boot = bootStatus(observed.Boot)
+		managed := map[string]string{ownerCategoryKey: string(vm.GetUID())}
+		for k, v := range protection {
+			managed[k] = v
+		}
+		var remove []string
+		if group := vm.Spec.AntiAffinityGroup; group != "" {
+			managed[antiAffinityCategoryKey] = group
+		} else if _, ok := vm.Spec.Categories[antiAffinityCategoryKey]; !ok {
+			remove = append(remove, antiAffinityCategoryKey)
+		}
+		if err := r.updateManagedCategories(ctx, ntxCli, &vm, observed, managed, remove); err != nil {
+			return r.fail(ctx, &vm, reasonCannotObserve, err)
+		}
+		if err := r.handleDrift(ctx, ntxCli, &vm, observed); err != nil {
+			r.log.Debug("Failed to correct VM drift", "error", err)
+			return r.fail(ctx, &vm, reasonCannotCorrectDrift, err)
+		}
+	}
+	if err := r.reconcileGuestTools(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotManageGuestTools, err)
+	}
+	if err := r.reconcileVolumeGroups(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotAttachVolumeGroups, err)
+	}
+	if err := r.reconcileProtection(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotObserve, err)
+	}
+	if err := r.reconcileFloatingIP(ctx, ntxCli, &vm); err != nil {
+		return r.fail(ctx, &vm, reasonCannotAssignFloatingIP, err)
+	}
+	if _, err := r.publishConnection(ctx, &vm, observed); err != nil {
+		return r.fail(ctx, &vm, reasonCannotPublishConnection, err)
+	}
+
+	ready, guest, err := checkGuestReadiness(ctx, &vm, observed)
+	if err != nil {
+		return r.fail(ctx, &vm, reasonGuestNotReady, err)
+	}
+	if !ready {
+		requeue := guestRetryInterval
+		if guestReadinessTimedOut(&vm) {
+			if vm.GetCondition(v1alpha1.TypeGuestReady).Reason != v1alpha1.ReasonGuestTimedOut {
+				r.record.Event(&vm, event.Warning(reasonGuestNotReady, fmt.Errorf("guest not ready: %s", guest.Message)))
 			}
-			return reconcile.Result{}, fmt.Errorf("availabilityZone '%s' is not recognized. Allowed values: %v", vm.Spec.AvailabilityZone, allowed)
+			guest = v1alpha1.GuestNotReady(v1alpha1.ReasonGuestTimedOut, guest.Message)
+			vm.SetConditions(xpv1.Unavailable().WithMessage(guest.Message))
+			requeue = r.pollInterval
+		} else {
+			vm.SetConditions(xpv1.Creating())
 		}
-		if !enabled {
-			return reconcile.Result{}, fmt.Errorf("availabilityZone '%s' is currently disabled and cannot be used for VM deployment", vm.Spec.AvailabilityZone)
+		vm.SetConditions(guest, xpv1.ReconcileSuccess())
+		if err := r.Status().Update(ctx, &vm); err != nil {
+			return reconcile.Result{}, err
 		}
-		cluster, ok := mapping[vm.Spec.AvailabilityZone]
-		if !ok {
-			return reconcile.Result{}, fmt.Errorf("internal error: enabled availabilityZone '%s' not mapped to a cluster", vm.Spec.AvailabilityZone)
+		return reconcile.Result{RequeueAfter: requeue}, nil
+	}
+	if guest.Reason == v1alpha1.ReasonGuestReady && vm.GetCondition(v1alpha1.TypeGuestReady).Reason != v1alpha1.ReasonGuestReady {
+		r.record.Event(&vm, event.Normal(reasonGuestReady, guest.Message))
+	}
+	vm.SetConditions(guest, xpv1.Available(), xpv1.ReconcileSuccess())
+	if err := r.Status().Update(ctx, &vm); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+}
+
+// powerOffDriftFields are the drift fields Prism only corrects on powered
+// off VMs.
+var powerOffDriftFields = map[string]bool{
+	v1alpha1.DriftFieldBoot:   true,
+	v1alpha1.DriftFieldCPU:    true,
+	v1alpha1.DriftFieldMemory: true,
+}
+
+// handleDrift records which fields of the observed VM differ from its spec
+// and, unless the VM's drift policy is ReportOnly, reverts them in Prism.
+func (r *VirtualMachineReconciler) handleDrift(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine, observed *nutanix.VMInfo) (err error) {
+	ctx, span := tracing.Start(ctx, "HandleDrift", tracing.AttrVMUUID.String(vm.Status.VMID))
+	defer func() { tracing.End(span, err) }()
+
+	policy := v1alpha1.DriftPolicy{Mode: v1alpha1.DriftModeEnforce}
+	if vm.Spec.DriftPolicy != nil {
+		policy = *vm.Spec.DriftPolicy
+		if policy.Mode == "" {
+			policy.Mode = v1alpha1.DriftModeEnforce
+		}
+	}
+
+	now := metav1.Now()
+	vm.Status.LastDriftCheckTime = &now
+	vm.Status.Drift = computeDrift(withOwnerCategory(vm), observed, policy.IgnoreFields)
+	if len(vm.Status.Drift) == 0 {
+		return nil
+	}
+	fields := strings.Join(driftFields(vm.Status.Drift), ", ")
+	span.SetAttributes(attribute.StringSlice("nutanix.drift.fields", driftFields(vm.Status.Drift)))
+
+	if policy.Mode == v1alpha1.DriftModeReportOnly {
+		r.record.Event(vm, event.Normal(reasonDriftDetected, "Detected drift in "+fields))
+		return nil
+	}
+
+	// Prism only changes the boot settings, CPU topology and memory options
+	// of powered off VMs, so their drift on a running VM is left in status
+	// until it is powered off.
+	update, power, boot := false, false, false
+	corrected := make([]string, 0, len(vm.Status.Drift))
+	var deferred []string
+	for _, d := range vm.Status.Drift {
+		switch {
+		case d.Field == v1alpha1.DriftFieldPowerState:
+			power = true
+		case powerOffDriftFields[d.Field] && observed.PowerState != "OFF":
+			deferred = append(deferred, d.Field)
+			continue
+		case d.Field == v1alpha1.DriftFieldBoot:
+			boot = true
+		default:
+			update = true
+		}
+		corrected = append(corrected, d.Field)
+	}
+	if len(deferred) > 0 {
+		r.record.Event(vm, event.Normal(reasonDriftDetected, fmt.Sprintf("Detected drift in %s; it is corrected once the VM is powered off", strings.Join(deferred, ", "))))
+	}
+	if len(corrected) == 0 {
+		return nil
+	}
+	if boot {
+		if err := ntxCli.UpdateBootConfig(ctx, vm.Status.VMID, vm.Spec.Boot, vm.Spec.VTPM); err != nil {
+			return err
+		}
+	}
+	if update {
+		// UpdateVM replaces the whole VM, so ignored and deferred fields are
+		// sent as observed rather than reverted along with the drift.
+		keep := append(append([]string{}, policy.IgnoreFields...), deferred...)
+		spec := keepObserved(withOwnerCategory(vm), observed, keep)
+		if err := ntxCli.UpdateVM(ctx, vm.Status.VMID, spec); err != nil {
+			return err
+		}
+	}
+	if power {
+		if err := ntxCli.SetPowerState(ctx, vm.Status.VMID, vm.Spec.PowerState); err != nil {
+			return err
 		}
-		vm.Spec.ClusterName = cluster
 	}
+	r.record.Event(vm, event.Normal(reasonDriftCorrected, "Corrected drift in "+strings.Join(corrected, ", ")))
+	return nil
+}
+
+// fail records a warning event for err, sets the supplied conditions along
+// with a failed Synced condition, and persists the VirtualMachine's status.
+func (r *VirtualMachineReconciler) fail(ctx context.Context, vm *v1alpha1.VirtualMachine, reason event.Reason, err error, c ...xpv1.Condition) (reconcile.Result, error) {
+	r.record.Event(vm, event.Warning(reason, err))
+	vm.SetConditions(append(c, xpv1.ReconcileError(err))...)
+	if uerr := r.Status().Update(ctx, vm); uerr != nil {
+		r.log.Debug("Failed to update VM status", "error", uerr)
+	}
+	return reconcile.Result{}, err
+}
+
+// mapAvailabilityZone sets the cluster name from the ProviderConfig's
+// availability zone mapping when the VM asks for an availabilityZone.
+func (r *VirtualMachineReconciler) mapAvailabilityZone(ctx context.Context, pc *v1beta1.ProviderConfig, vm *v1alpha1.VirtualMachine) (err error) {
+	if vm.Spec.AvailabilityZone == "" || !pc.Spec.EnableAvailabilityZoneMapping {
+		return nil
+	}
+	ctx, span := tracing.Start(ctx, "MapAvailabilityZone", attribute.String("nutanix.availability_zone", vm.Spec.AvailabilityZone))
+	defer func() { tracing.End(span, err) }()
 
-	// LoB validation logic
+	mappingURL := pc.Spec.AvailabilityZoneMappingURL
+	if mappingURL == "" {
+		return fmt.Errorf("availabilityZone specified but ProviderConfig does not have availabilityZoneMappingURL set")
+	}
+	mapping, enabledMap, err := fetchAvailabilityZoneMapping(ctx, mappingURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch availability zone mapping: %v", err)
+	}
+	enabled, found := enabledMap[vm.Spec.AvailabilityZone]
+	if !found {
+		allowed := make([]string, 0, len(enabledMap))
+		for k := range enabledMap {
+			allowed = append(allowed, k)
+		}
+		return fmt.Errorf("availabilityZone '%s' is not recognized. Allowed values: %v", vm.Spec.AvailabilityZone, allowed)
+	}
+	if !enabled {
+		return fmt.Errorf("availabilityZone '%s' is currently disabled and cannot be used for VM deployment", vm.Spec.AvailabilityZone)
+	}
+	cluster, ok := mapping[vm.Spec.AvailabilityZone]
+	if !ok {
+		return fmt.Errorf("internal error: enabled availabilityZone '%s' not mapped to a cluster", vm.Spec.AvailabilityZone)
+	}
+	span.SetAttributes(tracing.AttrCluster.String(cluster))
+	vm.Spec.ClusterName = cluster
+	return nil
+}
+
+// validateLoB enforces the ProviderConfig's Line of Business rules.
+func validateLoB(pc *v1beta1.ProviderConfig, vm *v1alpha1.VirtualMachine) error {
 	if pc.Spec.IsLoBMandatory && vm.Spec.LoB == "" {
-		return reconcile.Result{}, fmt.Errorf("LoB is mandatory but not provided")
+		return fmt.Errorf("LoB is mandatory but not provided")
 	}
 	if vm.Spec.LoB != "" {
 		found := false
@@ -187,26 +615,43 @@ func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req reconcile.
 			}
 		}
 		if !found {
-			return reconcile.Result{}, fmt.Errorf("LoB value '%s' is not in the allowed list: %v", vm.Spec.LoB, pc.Spec.AllowedLoBs)
+			return fmt.Errorf("LoB value '%s' is not in the allowed list: %v", vm.Spec.LoB, pc.Spec.AllowedLoBs)
 		}
 	}
+	return nil
+}
 
-	// Enforce datacenter validation: only allow datacenters listed in ProviderConfig.PrismCentralEndpoints
-	var currentCreds v1beta1.ProviderCredentials
-	if vm.Spec.Datacenter != "" {
-		if len(pc.Spec.PrismCentralEndpoints) == 0 {
-			return reconcile.Result{}, fmt.Errorf("datacenter specified in VM spec, but no PrismCentralEndpoints configured in ProviderConfig")
-		}
-		if _, ok := pc.Spec.PrismCentralEndpoints[vm.Spec.Datacenter]; !ok {
-			// Build allowed datacenter list for error message
-			allowed := make([]string, 0, len(pc.Spec.PrismCentralEndpoints))
-			for k := range pc.Spec.PrismCentralEndpoints {
-				allowed = append(allowed, k)
-			}
-			return reconcile.Result{}, fmt.Errorf("datacenter '%s' is not allowed. Allowed values: %v", vm.Spec.Datacenter, allowed)
+// validateDatacenter only allows datacenters listed in the ProviderConfig's
+// PrismCentralEndpoints.
+func validateDatacenter(pc *v1beta1.ProviderConfig, vm *v1alpha1.VirtualMachine) error {
+	if vm.Spec.Datacenter == "" {
+		return nil
+	}
+	if len(pc.Spec.PrismCentralEndpoints) == 0 {
+		return fmt.Errorf("datacenter specified in VM spec, but no PrismCentralEndpoints configured in ProviderConfig")
+	}
+	if _, ok := pc.Spec.PrismCentralEndpoints[vm.Spec.Datacenter]; !ok {
+		// Build allowed datacenter list for error message
+		allowed := make([]string, 0, len(pc.Spec.PrismCentralEndpoints))
+		for k := range pc.Spec.PrismCentralEndpoints {
+			allowed = append(allowed, k)
 		}
+		return fmt.Errorf("datacenter '%s' is not allowed. Allowed values: %v", vm.Spec.Datacenter, allowed)
+	}
+	return nil
+}
+
+// connectPrism selects the Prism Central endpoint and credentials for
+// datacenter from the ProviderConfig and returns a client for it. Calls made
+// by the client wait on limiter.
+func connectPrism(ctx context.Context, kube client.Client, pc *v1beta1.ProviderConfig, datacenter string, limiter *rate.Limiter) (_ *nutanix.Client, err error) {
+	ctx, span := tracing.Start(ctx, "Connect", attribute.String("nutanix.datacenter", datacenter))
+	defer func() { tracing.End(span, err) }()
+
+	var currentCreds v1beta1.ProviderCredentials
+	if datacenter != "" {
 		// Only allow datacenters that are present in PrismCentralEndpoints
-		if dcCreds, ok := pc.Spec.DatacenterCredentials[vm.Spec.Datacenter]; ok {
+		if dcCreds, ok := pc.Spec.DatacenterCredentials[datacenter]; ok {
 			currentCreds = dcCreds
 		} else {
 			currentCreds = pc.Spec.Credentials
@@ -217,13 +662,13 @@ func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req reconcile.
 	}
 
 	if currentCreds.Source != "Secret" {
-		return reconcile.Result{}, fmt.Errorf("only Secret credentials source is supported")
+		return nil, fmt.Errorf("only Secret credentials source is supported")
 	}
 	secretRef := currentCreds.SecretRef
 
 	var secret corev1.Secret
-	if err := r.Get(ctx, client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}, &secret); err != nil {
-		return reconcile.Result{}, err
+	if err := kube.Get(ctx, client.ObjectKey{Namespace: secretRef.Namespace, Name: secretRef.Name}, &secret); err != nil {
+		return nil, err
 	}
 	creds := struct {
 		Endpoint string `json:"endpoint"`
@@ -232,79 +677,82 @@ func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req reconcile.
 		Insecure bool   `json:"insecure"`
 	}{}
 	if err := json.Unmarshal(secret.Data[secretRef.Key], &creds); err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
 
 	// Determine the Prism Central endpoint to use
 	var prismCentralEndpoint string
-	if vm.Spec.Datacenter != "" {
-		if len(pc.Spec.PrismCentralEndpoints) == 0 {
-			return reconcile.Result{}, fmt.Errorf("datacenter specified in VM spec, but no PrismCentralEndpoints configured in ProviderConfig")
-		}
+	if datacenter != "" {
 		var ok bool
-		prismCentralEndpoint, ok = pc.Spec.PrismCentralEndpoints[vm.Spec.Datacenter]
+		prismCentralEndpoint, ok = pc.Spec.PrismCentralEndpoints[datacenter]
 		if !ok {
-			return reconcile.Result{}, fmt.Errorf("datacenter '%s' not found in ProviderConfig's PrismCentralEndpoints map", vm.Spec.Datacenter)
+			return nil, fmt.Errorf("datacenter '%s' not found in ProviderConfig's PrismCentralEndpoints map", datacenter)
 		}
 	} else if creds.Endpoint != "" {
 		// Fallback to direct endpoint from credentials if no datacenter is specified
 		prismCentralEndpoint = creds.Endpoint
 	} else {
-		return reconcile.Result{}, fmt.Errorf("no datacenter specified in VM spec and no default endpoint in credentials")
+		return nil, fmt.Errorf("no datacenter specified and no default endpoint in credentials")
 	}
+	span.SetAttributes(tracing.AttrEndpoint.String(prismCentralEndpoint))
 
 	ntxCli := nutanix.NewClient(prismCentralEndpoint, creds.Username, creds.Password, creds.Insecure)
+	ntxCli.RateLimiter = limiter
+	return ntxCli, nil
+}
 
-	if !vm.DeletionTimestamp.IsZero() {
-		// Handle delete
-		if err := ntxCli.DeleteVM(ctx, vm.Status.VMID); err != nil {
-			r.log.Debug("Failed to delete VM", "error", err)
-			return reconcile.Result{}, err
-		}
-		return reconcile.Result{}, nil
-	}
-
-	if vm.Status.VMID == "" {
-		// Handle create
-		id, err := ntxCli.CreateVM(ctx, vm.Spec)
-		if err != nil {
-			r.log.Debug("Failed to create VM", "error", err)
-			return reconcile.Result{}, err
-		}
-		vm.Status.VMID = id
-		vm.Status.State = "Created"
-		if err := r.Status().Update(ctx, &vm); err != nil {
-			return reconcile.Result{}, err
-		}
-		return reconcile.Result{}, nil
-	}
+// resolveCluster fills in the cluster UUID from the mounted cluster details
+// file, falling back to a Prism lookup by name.
+func (r *VirtualMachineReconciler) resolveCluster(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	ctx, span := tracing.Start(ctx, "ResolveCluster", tracing.AttrCluster.String(vm.Spec.ClusterName))
+	defer func() { tracing.End(span, err) }()
 
 	// Assume cluster name is provided in the VirtualMachine spec
 	clusterName := vm.Spec.ClusterName
 	if clusterName == "" {
 		r.log.Debug("Cluster name not specified in VirtualMachine spec")
-		return reconcile.Result{}, fmt.Errorf("cluster name is required")
+		return fmt.Errorf("cluster name is required")
 	}
 
 	// Fetch cluster details dynamically from JSON file
 	clusterDetails, err := readDetailsByName("cluster", clusterName)
 	if err != nil {
 		r.log.Debug("Failed to read cluster details", "error", err)
-		return reconcile.Result{}, err
+		return err
 	}
 	clusterUuid, err := getValue(clusterDetails, "uuid")
 	if err != nil {
 		r.log.Debug("Failed to get cluster uuid from details", "error", err)
-		return reconcile.Result{}, err
+		return err
 	}
 	vm.Spec.ClusterUUID = clusterUuid
 
+	// If ClusterUUID is not set but ClusterName is, resolve the latest matching cluster
+	if vm.Spec.ClusterUUID == "" && vm.Spec.ClusterName != "" {
+		clusterUUID, err := fetchClusterUUID(ctx, ntxCli, vm.Spec.ClusterName)
+		if err != nil {
+			r.log.Debug("No matching cluster found for name", "clusterName", vm.Spec.ClusterName, "error", err)
+			return fmt.Errorf("no cluster found matching name: %s", vm.Spec.ClusterName)
+		}
+		vm.Spec.ClusterUUID = clusterUUID
+	}
+	return nil
+}
+
+// resolveImages resolves the boot image and any additional disk images by
+// partial name, picking the most recently created match. The resolution is
+// only recorded as an event before the VM is created, as it is repeated on
+// every reconcile.
+func (r *VirtualMachineReconciler) resolveImages(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	ctx, span := tracing.Start(ctx, "ResolveImages", attribute.String("nutanix.image.name", vm.Spec.ImageName))
+	defer func() { tracing.End(span, err) }()
+
 	// If ImageUUID is not set but ImageName is, resolve the latest matching image
 	if vm.Spec.ImageUUID == "" && vm.Spec.ImageName != "" {
 		images, err := ntxCli.ListImages(ctx)
 		if err != nil {
 			r.log.Debug("Failed to list images", "error", err)
-			return reconcile.Result{}, err
+			return err
 		}
 		var latestImage *nutanix.ImageInfo
 		for _, img := range images {
@@ -316,70 +764,12 @@ func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req reconcile.
 		}
 		if latestImage == nil {
 			r.log.Debug("No matching image found for partial name", "imageName", vm.Spec.ImageName)
-			return reconcile.Result{}, fmt.Errorf("no image found matching name: %s", vm.Spec.ImageName)
+			return fmt.Errorf("no image found matching name: %s", vm.Spec.ImageName)
 		}
 		vm.Spec.ImageUUID = latestImage.UUID
-	}
-
-	// If SubnetUUID is not set but SubnetName is, resolve the latest matching subnet
-	if vm.Spec.SubnetUUID == "" && vm.Spec.SubnetName != "" {
-		subnets, err := ntxCli.ListSubnets(ctx)
-		if err != nil {
-			r.log.Debug("Failed to list subnets", "error", err)
-			return reconcile.Result{}, err
+		if vm.Status.VMID == "" {
+			r.record.Event(vm, event.Normal(reasonResolvedImage, fmt.Sprintf("Resolved image %q to %s (%s)", vm.Spec.ImageName, latestImage.Name, latestImage.UUID)))
 		}
-		var latestSubnet *nutanix.SubnetInfo
-		for _, sn := range subnets {
-			if sn.Name != "" && vm.Spec.SubnetName != "" && containsIgnoreCase(sn.Name, vm.Spec.SubnetName) {
-				if latestSubnet == nil || sn.CreatedTime > latestSubnet.CreatedTime {
-					latestSubnet = &sn
-				}
-			}
-		}
-		if latestSubnet == nil {
-			r.log.Debug("No matching subnet found for partial name", "subnetName", vm.Spec.SubnetName)
-			return reconcile.Result{}, fmt.Errorf("no subnet found matching name: %s", vm.Spec.SubnetName)
-		}
-
-		// Enforce allowed_repos restriction from subnet JSON file
-		// Use label 'repo' on the VM as the repo identifier
-		repoName := ""
-		if val, ok := vm.Labels["repo"]; ok {
-			repoName = val
-		}
-		details, err := readDetailsByName("network", latestSubnet.Name)
-		if err == nil {
-			if allowed, ok := details["allowed_repos"]; ok {
-				if allowedList, ok := allowed.([]interface{}); ok {
-					if len(allowedList) > 0 {
-						repoAllowed := false
-						if repoName != "" {
-							for _, v := range allowedList {
-								if s, ok := v.(string); ok && s == repoName {
-									repoAllowed = true
-									break
-								}
-							}
-						}
-						if !repoAllowed {
-							return reconcile.Result{}, fmt.Errorf("repo '%s' is not allowed to use subnet '%s'", repoName, latestSubnet.Name)
-						}
-					} // else: allowed_repos is empty, allow any repo
-				}
-			}
-		}
-
-		vm.Spec.SubnetUUID = latestSubnet.UUID
-	}
-
-	// If ClusterUUID is not set but ClusterName is, resolve the latest matching cluster
-	if vm.Spec.ClusterUUID == "" && vm.Spec.ClusterName != "" {
-		clusterUUID, err := fetchClusterUUID(ntxCli, vm.Spec.ClusterName)
-		if err != nil {
-			r.log.Debug("No matching cluster found for name", "clusterName", vm.Spec.ClusterName, "error", err)
-			return reconcile.Result{}, fmt.Errorf("no cluster found matching name: %s", vm.Spec.ClusterName)
-		}
-		vm.Spec.ClusterUUID = clusterUUID
 	}
 
 	// Resolve additionalDisks image UUIDs if needed
@@ -388,7 +778,7 @@ func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req reconcile.
 			images, err := ntxCli.ListImages(ctx)
 			if err != nil {
 				r.log.Debug("Failed to list images for additional disk", "error", err)
-				return reconcile.Result{}, err
+				return err
 			}
 			var latestImage *nutanix.ImageInfo
 			for _, img := range images {
@@ -400,33 +790,184 @@ func (r *VirtualMachineReconciler) Reconcile(ctx context.Context, req reconcile.
 			}
 			if latestImage == nil {
 				r.log.Debug("No matching image found for additional disk partial name", "imageName", disk.ImageName)
-				return reconcile.Result{}, fmt.Errorf("no image found matching name for additional disk: %s", disk.ImageName)
+				return fmt.Errorf("no image found matching name for additional disk: %s", disk.ImageName)
 			}
 			vm.Spec.AdditionalDisks[i].ImageUUID = latestImage.UUID
+			if vm.Status.VMID == "" {
+				r.record.Event(vm, event.Normal(reasonResolvedImage, fmt.Sprintf("Resolved image %q for disk %d to %s (%s)", disk.ImageName, disk.DeviceIndex, latestImage.Name, latestImage.UUID)))
+			}
 		}
 	}
+	return nil
+}
 
-	// Handle observe
-	_, err = ntxCli.GetVM(ctx, vm.Status.VMID)
+// resolveSubnet resolves the subnet by partial name, keeping existing VMs on
+// the subnet recorded in their status, and enforces the allowed_repos
+// restriction from the subnet's network details file.
+func (r *VirtualMachineReconciler) resolveSubnet(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	// If SubnetUUID is not set but SubnetName is, resolve the latest matching subnet
+	if vm.Spec.SubnetUUID != "" || vm.Spec.SubnetName == "" {
+		return nil
+	}
+	// An existing VM stays on the subnet it was resolved to, so that a newer
+	// subnet matching SubnetName is not reported as drift or moved to.
+	if sn := vm.Status.Subnet; sn != nil && vm.Status.VMID != "" && containsIgnoreCase(sn.Name, vm.Spec.SubnetName) {
+		vm.Spec.SubnetUUID = sn.UUID
+		vm.Spec.SubnetName = sn.Name
+		return nil
+	}
+	ctx, span := tracing.Start(ctx, "ResolveSubnet", attribute.String("nutanix.subnet.name", vm.Spec.SubnetName))
+	defer func() { tracing.End(span, err) }()
+
+	subnets, err := ntxCli.ListSubnets(ctx)
 	if err != nil {
-		r.log.Debug("Failed to get VM", "error", err)
-		return reconcile.Result{}, err
+		r.log.Debug("Failed to list subnets", "error", err)
+		return err
+	}
+	vpcUUID := ""
+	if vm.Spec.VPCName != "" {
+		vpcs, err := ntxCli.ListVPCs(ctx)
+		if err != nil {
+			return err
+		}
+		for _, vpc := range vpcs {
+			if vpc.Name == vm.Spec.VPCName {
+				vpcUUID = vpc.UUID
+				break
+			}
+		}
+		if vpcUUID == "" {
+			return fmt.Errorf("VPC %s not found", vm.Spec.VPCName)
+		}
+	}
+	var latestSubnet *nutanix.SubnetInfo
+	for _, sn := range subnets {
+		if vpcUUID != "" && sn.VPCUUID != vpcUUID {
+			continue
+		}
+		if sn.Name != "" && vm.Spec.SubnetName != "" && containsIgnoreCase(sn.Name, vm.Spec.SubnetName) {
+			if latestSubnet == nil || sn.CreatedTime > latestSubnet.CreatedTime {
+				latestSubnet = &sn
+			}
+		}
+	}
+	if latestSubnet == nil {
+		r.log.Debug("No matching subnet found for partial name", "subnetName", vm.Spec.SubnetName)
+		if vpcUUID != "" {
+			return fmt.Errorf("no subnet found matching name %s in VPC %s", vm.Spec.SubnetName, vm.Spec.VPCName)
+		}
+		return fmt.Errorf("no subnet found matching name: %s", vm.Spec.SubnetName)
+	}
+
+	// Enforce allowed_repos restriction from subnet JSON file
+	// Use label 'repo' on the VM as the repo identifier
+	repoName := ""
+	if val, ok := vm.Labels["repo"]; ok {
+		repoName = val
+	}
+	details, err := readDetailsByName("network", latestSubnet.Name)
+	if err == nil {
+		if allowed, ok := details["allowed_repos"]; ok {
+			if allowedList, ok := allowed.([]interface{}); ok {
+				if len(allowedList) > 0 {
+					repoAllowed := false
+					if repoName != "" {
+						for _, v := range allowedList {
+							if s, ok := v.(string); ok && s == repoName {
+								repoAllowed = true
+								break
+							}
+						}
+					}
+					if !repoAllowed {
+						return fmt.Errorf("repo '%s' is not allowed to use subnet '%s'", repoName, latestSubnet.Name)
+					}
+				} // else: allowed_repos is empty, allow any repo
+			}
+		}
 	}
-	return reconcile.Result{}, nil
+
+	vm.Spec.SubnetUUID = latestSubnet.UUID
+	vm.Spec.SubnetName = latestSubnet.Name
+	vm.Status.Subnet = &v1alpha1.VMSubnetStatus{UUID: latestSubnet.UUID, Name: latestSubnet.Name}
+	return nil
 }
 
 // containsIgnoreCase checks if s contains substr, case-insensitive
 func containsIgnoreCase(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || (len(substr) > 0 && containsIgnoreCase(s[1:], substr))) ||
-		(len(substr) > 0 && (len(s) > 0 && (s[0]|32) == (substr[0]|32) && containsIgnoreCase(s[1:], substr[1:])))
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func SetupVirtualMachine(mgr manager.Manager, o Options) error {
+	r := &VirtualMachineReconciler{
+		Client:       mgr.GetClient(),
+		log:          o.Logger,
+		record:       event.NewAPIRecorder(mgr.GetEventRecorderFor("managed/virtualmachine")),
+		pollInterval: o.PollInterval,
+		prismLimiter: o.PrismRateLimiter,
+	}
+	publishers := managed.PublisherChain{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		r.store = connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind)
+		publishers = append(publishers, r.store)
+	}
+	r.publisher = publishers
+
+	opts := o.ForControllerRuntime()
+	opts.Reconciler = r
+	c, err := controller.New("virtualmachine-controller", mgr, opts)
+	if err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &v1alpha1.VirtualMachine{}}, &handler.EnqueueRequestForObject{})
+}
+
+// selectProject sets the VM's project name from its Project reference or,
+// failing that, from the project the ProviderConfig maps its LoB to. VMs of
+// a mapped LoB may only use that LoB's project.
+func (r *VirtualMachineReconciler) selectProject(ctx context.Context, pc *v1beta1.ProviderConfig, vm *v1alpha1.VirtualMachine) error {
+	if ref := vm.Spec.ProjectRef; ref != nil {
+		var p v1alpha1.Project
+		if err := r.Get(ctx, client.ObjectKey{Name: ref.Name}, &p); err != nil {
+			return fmt.Errorf("cannot get referenced project %s: %w", ref.Name, err)
+		}
+		vm.Spec.ProjectName = p.Spec.Name
+		if uuid := meta.GetExternalName(&p); uuid != "" && vm.Spec.ProjectUUID == "" {
+			vm.Spec.ProjectUUID = uuid
+		}
+	}
+
+	mapped, ok := pc.Spec.LoBProjects[vm.Spec.LoB]
+	if vm.Spec.LoB == "" || !ok {
+		return nil
+	}
+	switch vm.Spec.ProjectName {
+	case "":
+		vm.Spec.ProjectName = mapped
+	case mapped:
+	default:
+		return fmt.Errorf("LoB '%s' must use project '%s', not '%s'", vm.Spec.LoB, mapped, vm.Spec.ProjectName)
+	}
+	return nil
 }
 
-func SetupVirtualMachine(mgr manager.Manager, l logging.Logger) error {
-	_, err := controller.New("virtualmachine-controller", mgr, controller.Options{
-		Reconciler: &VirtualMachineReconciler{
-			Client: mgr.GetClient(),
-			log:    l,
-		},
-	})
-	return err
+// resolveProject resolves the VM's project name to its Prism UUID.
+func (r *VirtualMachineReconciler) resolveProject(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	if vm.Spec.ProjectUUID != "" || vm.Spec.ProjectName == "" {
+		return nil
+	}
+	ctx, span := tracing.Start(ctx, "ResolveProject", attribute.String("nutanix.project.name", vm.Spec.ProjectName))
+	defer func() { tracing.End(span, err) }()
+
+	projects, err := ntxCli.ListProjects(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if p.Name == vm.Spec.ProjectName {
+			vm.Spec.ProjectUUID = p.UUID
+			return nil
+		}
+	}
+	return fmt.Errorf("project %s not found", vm.Spec.ProjectName)
 }
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+func TestResolveSubnet(t *testing.T) {
+	pinned := &v1alpha1.VMSubnetStatus{UUID: "subnet-uuid-2", Name: "dev-subnet"}
+
+	type want struct {
+		uuid, name string
+		status     *v1alpha1.VMSubnetStatus
+	}
+	cases := map[string]struct {
+		reason     string
+		subnetName string
+		status     v1alpha1.VirtualMachineStatus
+		want       want
+	}{
+		"NewVM": {
+			reason:     "A new VM uses the latest subnet matching subnetName and records it.",
+			subnetName: "subnet",
+			want:       want{uuid: "subnet-uuid-3", name: "rhel8-subnet", status: &v1alpha1.VMSubnetStatus{UUID: "subnet-uuid-3", Name: "rhel8-subnet"}},
+		},
+		"ExistingVM": {
+			reason:     "An existing VM stays on its recorded subnet when a newer subnet matches.",
+			subnetName: "subnet",
+			status:     v1alpha1.VirtualMachineStatus{VMID: "vm-uuid-1", Subnet: pinned},
+			want:       want{uuid: "subnet-uuid-2", name: "dev-subnet", status: pinned},
+		},
+		"SubnetNameChanged": {
+			reason:     "The recorded subnet is replaced when it no longer matches subnetName.",
+			subnetName: "prod",
+			status:     v1alpha1.VirtualMachineStatus{VMID: "vm-uuid-1", Subnet: pinned},
+			want:       want{uuid: "subnet-uuid-1", name: "prod-subnet", status: &v1alpha1.VMSubnetStatus{UUID: "subnet-uuid-1", Name: "prod-subnet"}},
+		},
+		"NotCreated": {
+			reason:     "A subnet recorded before the VM was created is resolved again.",
+			subnetName: "subnet",
+			status:     v1alpha1.VirtualMachineStatus{Subnet: pinned},
+			want:       want{uuid: "subnet-uuid-3", name: "rhel8-subnet", status: &v1alpha1.VMSubnetStatus{UUID: "subnet-uuid-3", Name: "rhel8-subnet"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &VirtualMachineReconciler{}
+			ntxCli := nutanix.NewClient("https://prism.example.com:9440", "admin", "secret", false)
+			vm := &v1alpha1.VirtualMachine{Spec: v1alpha1.VirtualMachineSpec{SubnetName: tc.subnetName}, Status: tc.status}
+			if err := r.resolveSubnet(context.Background(), ntxCli, vm); err != nil {
+				t.Fatalf("\n%s\nresolveSubnet(...): %v", tc.reason, err)
+			}
+			got := want{uuid: vm.Spec.SubnetUUID, name: vm.Spec.SubnetName, status: vm.Status.Subnet}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nresolveSubnet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupVirtualMachineSnapshot adds a controller that reconciles
+// VirtualMachineSnapshot resources.
+func SetupVirtualMachineSnapshot(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.VirtualMachineSnapshot](mgr, o, "VirtualMachineSnapshot",
+		func() *v1alpha1.VirtualMachineSnapshot { return &v1alpha1.VirtualMachineSnapshot{} }, &snapshotExternal{kube: mgr.GetClient()})
+}
+
+// snapshotExternal manages Prism VM recovery points. The external name is
+// the recovery point UUID. Recovery points are immutable apart from their
+// expiration.
+type snapshotExternal struct {
+	kube client.Client
+}
+
+func (e *snapshotExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VirtualMachineSnapshot) (externalObservation, error) {
+	observed, err := ntxCli.GetRecoveryPoint(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		// Prism deletes expired recovery points; report them rather than
+		// taking a new snapshot.
+		if exp := cr.Status.ExpirationTime; exp != nil && !time.Now().Before(exp.Time) {
+			cr.Status.State = v1alpha1.RecoveryPointStateExpired
+			return externalObservation{Exists: true, UpToDate: true}, nil
+		}
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	created := metav1.NewTime(observed.CreationTime)
+	cr.Status.VMUUID = observed.VMUUID
+	cr.Status.State = observed.State
+	cr.Status.Consistency = observed.Consistency
+	cr.Status.CreationTime = &created
+	cr.Status.ExpirationTime = nil
+	if observed.ExpirationTime != nil {
+		exp := metav1.NewTime(*observed.ExpirationTime)
+		cr.Status.ExpirationTime = &exp
+	}
+
+	desired := snapshotExpiration(cr, observed.CreationTime)
+	upToDate := (desired == nil) == (observed.ExpirationTime == nil) &&
+		(desired == nil || desired.Equal(*observed.ExpirationTime))
+	return externalObservation{
+		Exists:   true,
+		UpToDate: upToDate,
+		Pending:  observed.State != v1alpha1.RecoveryPointStateComplete,
+	}, nil
+}
+
+func (e *snapshotExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VirtualMachineSnapshot) (string, error) {
+	var vm v1alpha1.VirtualMachine
+	if err := e.kube.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: cr.Spec.VMRef.Name}, &vm); err != nil {
+		return "", fmt.Errorf("cannot get VirtualMachine %s: %w", cr.Spec.VMRef.Name, err)
+	}
+	if vm.Status.VMID == "" {
+		return "", fmt.Errorf("VirtualMachine %s has not been created yet", vm.Name)
+	}
+	if vm.Spec.Datacenter != cr.Spec.Datacenter {
+		return "", fmt.Errorf("datacenter %q does not match VirtualMachine %s datacenter %q", cr.Spec.Datacenter, vm.Name, vm.Spec.Datacenter)
+	}
+	if cr.Spec.Consistency == v1alpha1.ConsistencyApplication {
+		if err := checkVSSCapable(&vm); err != nil {
+			return "", err
+		}
+	}
+
+	name := cr.Spec.Name
+	if name == "" {
+		name = cr.Name
+	}
+	return ntxCli.CreateRecoveryPoint(ctx, vm.Status.VMID, name, cr.Spec.Consistency, snapshotExpiration(cr, time.Now()))
+}
+
+func (e *snapshotExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VirtualMachineSnapshot) error {
+	var created time.Time
+	if cr.Status.CreationTime != nil {
+		created = cr.Status.CreationTime.Time
+	}
+	return ntxCli.UpdateRecoveryPointExpiration(ctx, meta.GetExternalName(cr), snapshotExpiration(cr, created))
+}
+
+func (e *snapshotExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VirtualMachineSnapshot) error {
+	var vms v1alpha1.VirtualMachineList
+	if err := e.kube.List(ctx, &vms, client.InNamespace(cr.Namespace)); err != nil {
+		return fmt.Errorf("cannot list VirtualMachines: %w", err)
+	}
+	for _, vm := range vms.Items {
+		rf := vm.Spec.RestoreFrom
+		if rf != nil && rf.SnapshotRef != nil && rf.SnapshotRef.Name == cr.Name && vm.Status.VMID == "" {
+			return fmt.Errorf("VirtualMachine %s/%s is still being restored from snapshot %s", vm.Namespace, vm.Name, cr.Name)
+		}
+	}
+	if cr.Status.State == v1alpha1.RecoveryPointStateExpired {
+		return nil
+	}
+	if err := ntxCli.DeleteRecoveryPoint(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// snapshotExpiration returns when a recovery point created at created should
+// expire, or nil if it never expires.
+func snapshotExpiration(cr *v1alpha1.VirtualMachineSnapshot, created time.Time) *time.Time {
+	if cr.Spec.ExpiresAfter == nil {
+		return nil
+	}
+	exp := created.Add(cr.Spec.ExpiresAfter.Duration).Truncate(time.Second)
+	return &exp
+}
+
+// checkVSSCapable returns an error unless the VM's guest tools can quiesce
+// the guest for an application consistent snapshot.
+func checkVSSCapable(vm *v1alpha1.VirtualMachine) error {
+	gt := vm.Spec.GuestTools
+	if gt == nil || !gt.Enabled || !containsString(gt.Capabilities, v1alpha1.GuestToolsCapabilityVSSSnapshot) {
+		return fmt.Errorf("application consistent snapshots require guest tools with the %s capability on VirtualMachine %s",
+			v1alpha1.GuestToolsCapabilityVSSSnapshot, vm.Name)
+	}
+	if st := vm.Status.GuestTools; st != nil && !st.Reachable {
+		return fmt.Errorf("guest tools on VirtualMachine %s are not reachable", vm.Name)
+	}
+	return nil
+}
+
+// containsString reports whether s contains v.
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupVMTemplate adds a controller that reconciles VMTemplate resources.
+func SetupVMTemplate(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.VMTemplate](mgr, o, "VMTemplate",
+		func() *v1alpha1.VMTemplate { return &v1alpha1.VMTemplate{} }, &vmTemplateExternal{kube: mgr.GetClient()})
+}
+
+// vmTemplateExternal manages Prism VM templates and their versions. The
+// external name is the template UUID.
+type vmTemplateExternal struct {
+	kube client.Client
+}
+
+func (e *vmTemplateExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VMTemplate) (externalObservation, error) {
+	observed, err := ntxCli.GetTemplate(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.ActiveVersion = ""
+	if active := observed.ActiveVersion(); active != nil {
+		cr.Status.ActiveVersion = active.Name
+	}
+	cr.Status.Versions = nil
+	for _, v := range observed.Versions {
+		cr.Status.Versions = append(cr.Status.Versions, v1alpha1.VMTemplateVersion{Name: v.Name, UUID: v.UUID})
+	}
+	upToDate := observed.Name == cr.Spec.Name &&
+		observed.Description == cr.Spec.Description &&
+		cr.Status.ActiveVersion == cr.Spec.Version &&
+		(cr.Spec.MaxVersions == 0 || len(observed.Versions) <= cr.Spec.MaxVersions)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *vmTemplateExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VMTemplate) (string, error) {
+	vmUUID, err := e.sourceVMUUID(ctx, cr)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.CreateTemplate(ctx, cr.Spec.Name, cr.Spec.Description, vmUUID, cr.Spec.Version, cr.Spec.VersionDescription)
+}
+
+func (e *vmTemplateExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VMTemplate) error {
+	uuid := meta.GetExternalName(cr)
+	observed, err := ntxCli.GetTemplate(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if observed == nil {
+		return nil
+	}
+	if observed.Name != cr.Spec.Name || observed.Description != cr.Spec.Description {
+		if err := ntxCli.UpdateTemplate(ctx, uuid, cr.Spec.Name, cr.Spec.Description); err != nil {
+			return err
+		}
+	}
+
+	if active := observed.ActiveVersion(); active == nil || active.Name != cr.Spec.Version {
+		var existing *nutanix.TemplateVersionInfo
+		for i := range observed.Versions {
+			if observed.Versions[i].Name == cr.Spec.Version {
+				existing = &observed.Versions[i]
+			}
+		}
+		if existing != nil {
+			if err := ntxCli.SetActiveTemplateVersion(ctx, uuid, existing.UUID); err != nil {
+				return err
+			}
+		} else {
+			vmUUID, err := e.sourceVMUUID(ctx, cr)
+			if err != nil {
+				return err
+			}
+			versionUUID, err := ntxCli.PublishTemplateVersion(ctx, uuid, vmUUID, cr.Spec.Version, cr.Spec.VersionDescription)
+			if err != nil {
+				return err
+			}
+			observed.Versions = append(observed.Versions, nutanix.TemplateVersionInfo{UUID: versionUUID, Name: cr.Spec.Version})
+		}
+	}
+	return pruneTemplateVersions(ctx, ntxCli, uuid, observed.Versions, cr.Spec.Version, cr.Spec.MaxVersions)
+}
+
+func (e *vmTemplateExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VMTemplate) error {
+	var vms v1alpha1.VirtualMachineList
+	if err := e.kube.List(ctx, &vms, client.InNamespace(cr.Namespace)); err != nil {
+		return fmt.Errorf("cannot list VirtualMachines: %w", err)
+	}
+	for _, vm := range vms.Items {
+		src := vm.Spec.Source
+		if src != nil && src.TemplateRef != nil && src.TemplateRef.Name == cr.Name && vm.Status.VMID == "" {
+			return fmt.Errorf("VirtualMachine %s/%s is still being deployed from template %s", vm.Namespace, vm.Name, cr.Name)
+		}
+	}
+	if err := ntxCli.DeleteTemplate(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// sourceVMUUID returns the UUID of the golden VM versions of cr are
+// published from.
+func (e *vmTemplateExternal) sourceVMUUID(ctx context.Context, cr *v1alpha1.VMTemplate) (string, error) {
+	switch {
+	case cr.Spec.SourceVMRef != nil && cr.Spec.SourceVMUUID != "":
+		return "", fmt.Errorf("set only one of sourceVmRef and sourceVmUuid")
+	case cr.Spec.SourceVMUUID != "":
+		return cr.Spec.SourceVMUUID, nil
+	case cr.Spec.SourceVMRef == nil:
+		return "", fmt.Errorf("sourceVmRef or sourceVmUuid is required")
+	}
+	var vm v1alpha1.VirtualMachine
+	if err := e.kube.Get(ctx, client.ObjectKey{Namespace: cr.Namespace, Name: cr.Spec.SourceVMRef.Name}, &vm); err != nil {
+		return "", fmt.Errorf("cannot get VirtualMachine %s: %w", cr.Spec.SourceVMRef.Name, err)
+	}
+	if vm.Status.VMID == "" {
+		return "", fmt.Errorf("VirtualMachine %s has not been created yet", vm.Name)
+	}
+	if vm.Spec.Datacenter != cr.Spec.Datacenter {
+		return "", fmt.Errorf("datacenter %q does not match VirtualMachine %s datacenter %q", cr.Spec.Datacenter, vm.Name, vm.Spec.Datacenter)
+	}
+	return vm.Status.VMID, nil
+}
+
+// pruneTemplateVersions deletes the versions returned by
+// expiredTemplateVersions.
+func pruneTemplateVersions(ctx context.Context, ntxCli *nutanix.Client, uuid string, versions []nutanix.TemplateVersionInfo, active string, max int) error {
+	for _, v := range expiredTemplateVersions(versions, active, max) {
+		if err := ntxCli.DeleteTemplateVersion(ctx, uuid, v.UUID); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+			return fmt.Errorf("cannot delete template version %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// expiredTemplateVersions returns the oldest versions other than active that
+// must be deleted for at most max to remain. None are expired when max is
+// zero. Versions are ordered oldest first.
+func expiredTemplateVersions(versions []nutanix.TemplateVersionInfo, active string, max int) []nutanix.TemplateVersionInfo {
+	if max == 0 {
+		return nil
+	}
+	var expired []nutanix.TemplateVersionInfo
+	excess := len(versions) - max
+	for _, v := range versions {
+		if excess <= 0 {
+			break
+		}
+		if v.Name == active {
+			continue
+		}
+		expired = append(expired, v)
+		excess--
+	}
+	return expired
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+func TestExpiredTemplateVersions(t *testing.T) {
+	versions := func(names ...string) []nutanix.TemplateVersionInfo {
+		vs := make([]nutanix.TemplateVersionInfo, 0, len(names))
+		for _, n := range names {
+			vs = append(vs, nutanix.TemplateVersionInfo{UUID: n + "-uuid", Name: n})
+		}
+		return vs
+	}
+
+	cases := map[string]struct {
+		reason   string
+		versions []nutanix.TemplateVersionInfo
+		active   string
+		max      int
+		want     []nutanix.TemplateVersionInfo
+	}{
+		"Unlimited": {
+			reason:   "Nothing is pruned when max is zero.",
+			versions: versions("v1", "v2", "v3"),
+			active:   "v3",
+		},
+		"WithinLimit": {
+			reason:   "Nothing is pruned while at most max versions exist.",
+			versions: versions("v1", "v2"),
+			active:   "v2",
+			max:      2,
+		},
+		"OldestFirst": {
+			reason:   "The oldest versions are pruned first.",
+			versions: versions("v1", "v2", "v3", "v4"),
+			active:   "v4",
+			max:      2,
+			want:     versions("v1", "v2"),
+		},
+		"KeepsActive": {
+			reason:   "The active version is kept even when it is the oldest.",
+			versions: versions("v1", "v2", "v3"),
+			active:   "v1",
+			max:      1,
+			want:     versions("v2", "v3"),
+		},
+		"KeepsActiveInMiddle": {
+			reason:   "Versions older and newer than the active one are pruned around it.",
+			versions: versions("v1", "v2", "v3", "v4"),
+			active:   "v2",
+			max:      2,
+			want:     versions("v1", "v3"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := expiredTemplateVersions(tc.versions, tc.active, tc.max)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nexpiredTemplateVersions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
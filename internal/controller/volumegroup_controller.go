@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupVolumeGroup adds a controller that reconciles VolumeGroup resources.
+func SetupVolumeGroup(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.VolumeGroup](mgr, o, "VolumeGroup",
+		func() *v1alpha1.VolumeGroup { return &v1alpha1.VolumeGroup{} }, &volumeGroupExternal{kube: mgr.GetClient()})
+}
+
+// volumeGroupExternal manages Prism volume groups. The external name is the
+// volume group UUID. Attachments to VMs are managed by the VirtualMachine
+// controller and only reported here.
+type volumeGroupExternal struct {
+	kube client.Client
+}
+
+func (e *volumeGroupExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VolumeGroup) (externalObservation, error) {
+	observed, err := ntxCli.GetVolumeGroup(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.ISCSITargetName = observed.ISCSITargetName
+	cr.Status.AttachedVMs = sortedStrings(observed.AttachedVMUUIDs)
+
+	var initiators []string
+	if cr.Spec.ISCSI != nil {
+		initiators = cr.Spec.ISCSI.Initiators
+	}
+	upToDate := observed.Name == cr.Spec.Name &&
+		observed.Description == cr.Spec.Description &&
+		observed.Shared == cr.Spec.Shared &&
+		observed.FlashMode == cr.Spec.FlashMode &&
+		reflect.DeepEqual(sortedStrings(observed.Initiators), sortedStrings(initiators)) &&
+		disksUpToDate(observed.Disks, cr.Spec.Disks)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *volumeGroupExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VolumeGroup) (string, error) {
+	clusterUUID, err := fetchClusterUUID(ctx, ntxCli, cr.Spec.ClusterName)
+	if err != nil {
+		return "", err
+	}
+	chap, err := e.chapSecret(ctx, cr)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.CreateVolumeGroup(ctx, cr.Spec, clusterUUID, chap)
+}
+
+func (e *volumeGroupExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VolumeGroup) error {
+	chap, err := e.chapSecret(ctx, cr)
+	if err != nil {
+		return err
+	}
+	return ntxCli.UpdateVolumeGroup(ctx, meta.GetExternalName(cr), cr.Spec, chap)
+}
+
+func (e *volumeGroupExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VolumeGroup) error {
+	var vms v1alpha1.VirtualMachineList
+	if err := e.kube.List(ctx, &vms); err != nil {
+		return fmt.Errorf("cannot list VirtualMachines: %w", err)
+	}
+	for _, vm := range vms.Items {
+		for _, ref := range vm.Spec.VolumeGroupRefs {
+			if ref.Name == cr.Name {
+				return fmt.Errorf("volume group %s is still attached to VirtualMachine %s/%s", cr.Spec.Name, vm.Namespace, vm.Name)
+			}
+		}
+	}
+	if len(cr.Status.AttachedVMs) > 0 {
+		return fmt.Errorf("volume group %s is still attached to %d VMs in Prism", cr.Spec.Name, len(cr.Status.AttachedVMs))
+	}
+	if err := ntxCli.DeleteVolumeGroup(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// chapSecret returns the target CHAP secret referenced by the volume group's
+// iSCSI settings, or "" when none is set.
+func (e *volumeGroupExternal) chapSecret(ctx context.Context, cr *v1alpha1.VolumeGroup) (string, error) {
+	if cr.Spec.ISCSI == nil || cr.Spec.ISCSI.ChapSecretRef == nil {
+		return "", nil
+	}
+	ref := cr.Spec.ISCSI.ChapSecretRef
+	var s corev1.Secret
+	if err := e.kube.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &s); err != nil {
+		return "", fmt.Errorf("cannot get CHAP secret: %w", err)
+	}
+	return string(s.Data[ref.Key]), nil
+}
+
+// disksUpToDate reports whether every desired disk exists with at least its
+// desired size. Disks cannot be shrunk or removed, so larger or extra
+// observed disks are not drift.
+func disksUpToDate(observed, desired []v1alpha1.VolumeGroupDisk) bool {
+	sizes := make(map[int]int, len(observed))
+	for _, d := range observed {
+		sizes[d.Index] = d.SizeGb
+	}
+	for _, d := range desired {
+		if size, ok := sizes[d.Index]; !ok || size < d.SizeGb {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedStrings returns a sorted copy of s.
+func sortedStrings(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	out := append([]string(nil), s...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// reconcileVolumeGroups attaches the volume groups referenced by
+// spec.volumeGroupRefs to the VM and detaches those it attached earlier that
+// are no longer referenced. Volume groups attached outside the provider are
+// left alone.
+func (r *VirtualMachineReconciler) reconcileVolumeGroups(ctx context.Context, ntxCli *nutanix.Client, vm *v1alpha1.VirtualMachine) (err error) {
+	ctx, span := tracing.Start(ctx, "ReconcileVolumeGroups", tracing.AttrVMUUID.String(vm.Status.VMID))
+	defer func() { tracing.End(span, err) }()
+
+	previous := make(map[string]bool, len(vm.Status.VolumeGroups))
+	for _, uuid := range vm.Status.VolumeGroups {
+		previous[uuid] = true
+	}
+
+	desired := make(map[string]bool, len(vm.Spec.VolumeGroupRefs))
+	for _, ref := range vm.Spec.VolumeGroupRefs {
+		var vg v1alpha1.VolumeGroup
+		if err := r.Get(ctx, client.ObjectKey{Name: ref.Name}, &vg); err != nil {
+			return fmt.Errorf("cannot get VolumeGroup %s: %w", ref.Name, err)
+		}
+		uuid := meta.GetExternalName(&vg)
+		if uuid == "" {
+			return fmt.Errorf("VolumeGroup %s has not been created yet", ref.Name)
+		}
+		desired[uuid] = true
+
+		isAttached := previous[uuid]
+		observed, err := ntxCli.GetVolumeGroup(ctx, uuid)
+		if err != nil {
+			return fmt.Errorf("cannot get volume group %s: %w", vg.Spec.Name, err)
+		}
+		if observed != nil {
+			isAttached = containsString(observed.AttachedVMUUIDs, vm.Status.VMID)
+			if !isAttached && !vg.Spec.Shared && len(observed.AttachedVMUUIDs) > 0 {
+				return fmt.Errorf("volume group %s is not shared and already attached to VM %s", vg.Spec.Name, observed.AttachedVMUUIDs[0])
+			}
+		}
+		if !isAttached {
+			if err := ntxCli.AttachVolumeGroup(ctx, uuid, vm.Status.VMID); err != nil {
+				return fmt.Errorf("cannot attach volume group %s: %w", vg.Spec.Name, err)
+			}
+			r.record.Event(vm, event.Normal(reasonVolumeGroupsUpdated, fmt.Sprintf("Attached volume group %s (%s)", vg.Spec.Name, uuid)))
+		}
+		// Record each change right away so a later failure does not repeat
+		// it on the next reconcile.
+		previous[uuid] = true
+		vm.Status.VolumeGroups = attachedVolumeGroups(previous)
+	}
+
+	for uuid := range previous {
+		if desired[uuid] {
+			continue
+		}
+		if err := ntxCli.DetachVolumeGroup(ctx, uuid, vm.Status.VMID); err != nil {
+			return fmt.Errorf("cannot detach volume group %s: %w", uuid, err)
+		}
+		r.record.Event(vm, event.Normal(reasonVolumeGroupsUpdated, fmt.Sprintf("Detached volume group %s", uuid)))
+		delete(previous, uuid)
+		vm.Status.VolumeGroups = attachedVolumeGroups(previous)
+	}
+	return nil
+}
+
+// attachedVolumeGroups returns the sorted UUIDs in attached.
+func attachedVolumeGroups(attached map[string]bool) []string {
+	uuids := make([]string, 0, len(attached))
+	for uuid := range attached {
+		uuids = append(uuids, uuid)
+	}
+	return sortedStrings(uuids)
+}
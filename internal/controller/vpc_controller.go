@@ -0,0 +1,365 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupVPC adds a controller that reconciles VPC resources.
+func SetupVPC(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.VPC](mgr, o, "VPC",
+		func() *v1alpha1.VPC { return &v1alpha1.VPC{} }, &vpcExternal{kube: mgr.GetClient()})
+}
+
+// SetupFloatingIP adds a controller that reconciles FloatingIP resources.
+func SetupFloatingIP(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.FloatingIP](mgr, o, "FloatingIP",
+		func() *v1alpha1.FloatingIP { return &v1alpha1.FloatingIP{} }, &floatingIPExternal{kube: mgr.GetClient()})
+}
+
+// SetupRoutingPolicy adds a controller that reconciles RoutingPolicy resources.
+func SetupRoutingPolicy(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.RoutingPolicy](mgr, o, "RoutingPolicy",
+		func() *v1alpha1.RoutingPolicy { return &v1alpha1.RoutingPolicy{} }, &routingPolicyExternal{kube: mgr.GetClient()})
+}
+
+// vpcExternal manages Flow Virtual Networking VPCs. The external name is the
+// VPC UUID.
+type vpcExternal struct {
+	kube client.Client
+}
+
+func (e *vpcExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPC) (externalObservation, error) {
+	observed, err := ntxCli.GetVPC(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.SNATIPs = observed.SNATIPs
+	desired, err := vpcInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return externalObservation{}, err
+	}
+	upToDate := observed.Name == desired.Name &&
+		observed.Description == desired.Description &&
+		reflect.DeepEqual(sortedStrings(observed.ExternalSubnetUUIDs), sortedStrings(desired.ExternalSubnetUUIDs)) &&
+		reflect.DeepEqual(sortedStrings(observed.ExternallyRoutablePrefixes), sortedStrings(desired.ExternallyRoutablePrefixes)) &&
+		reflect.DeepEqual(observed.DNSServers, desired.DNSServers) &&
+		formatCategories(observed.Categories) == formatCategories(desired.Categories)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *vpcExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPC) (string, error) {
+	vpc, err := vpcInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.PutVPC(ctx, vpc)
+}
+
+func (e *vpcExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPC) error {
+	vpc, err := vpcInfo(ctx, ntxCli, cr)
+	if err != nil {
+		return err
+	}
+	vpc.UUID = meta.GetExternalName(cr)
+	_, err = ntxCli.PutVPC(ctx, vpc)
+	return err
+}
+
+func (e *vpcExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPC) error {
+	if user, err := e.user(ctx, cr); err != nil || user != "" {
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("VPC %s is still used by %s", cr.Spec.Name, user)
+	}
+	if err := ntxCli.DeleteVPC(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// user returns the kind and name of a resource referencing the VPC, or "" if
+// there is none.
+func (e *vpcExternal) user(ctx context.Context, cr *v1alpha1.VPC) (string, error) {
+	var subnets v1alpha1.SubnetList
+	if err := e.kube.List(ctx, &subnets); err != nil {
+		return "", fmt.Errorf("cannot list Subnets: %w", err)
+	}
+	for _, s := range subnets.Items {
+		if s.Spec.VPCRef != nil && s.Spec.VPCRef.Name == cr.Name {
+			return "Subnet " + s.Name, nil
+		}
+	}
+	var fips v1alpha1.FloatingIPList
+	if err := e.kube.List(ctx, &fips); err != nil {
+		return "", fmt.Errorf("cannot list FloatingIPs: %w", err)
+	}
+	for _, f := range fips.Items {
+		if f.Spec.VPCRef != nil && f.Spec.VPCRef.Name == cr.Name {
+			return "FloatingIP " + f.Name, nil
+		}
+	}
+	var policies v1alpha1.RoutingPolicyList
+	if err := e.kube.List(ctx, &policies); err != nil {
+		return "", fmt.Errorf("cannot list RoutingPolicies: %w", err)
+	}
+	for _, p := range policies.Items {
+		if p.Spec.VPCRef.Name == cr.Name {
+			return "RoutingPolicy " + p.Name, nil
+		}
+	}
+	var gateways v1alpha1.VPNGatewayList
+	if err := e.kube.List(ctx, &gateways); err != nil {
+		return "", fmt.Errorf("cannot list VPNGateways: %w", err)
+	}
+	for _, g := range gateways.Items {
+		if g.Spec.VPCRef.Name == cr.Name {
+			return "VPNGateway " + g.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// vpcInfo returns the VPC described by cr, with its external subnets
+// resolved to UUIDs.
+func vpcInfo(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPC) (nutanix.VPCInfo, error) {
+	vpc := nutanix.VPCInfo{
+		Name:                       cr.Spec.Name,
+		Description:                cr.Spec.Description,
+		ExternallyRoutablePrefixes: cr.Spec.ExternallyRoutablePrefixes,
+		DNSServers:                 cr.Spec.DNSServers,
+		Categories:                 cr.Spec.Categories,
+	}
+	for _, prefix := range cr.Spec.ExternallyRoutablePrefixes {
+		if _, _, err := net.ParseCIDR(prefix); err != nil {
+			return vpc, fmt.Errorf("invalid externally routable prefix %q: %w", prefix, err)
+		}
+	}
+	if len(cr.Spec.ExternalSubnetNames) == 0 {
+		return vpc, nil
+	}
+	subnets, err := ntxCli.ListSubnets(ctx)
+	if err != nil {
+		return vpc, err
+	}
+	for _, name := range cr.Spec.ExternalSubnetNames {
+		uuid, err := subnetUUIDByName(subnets, name)
+		if err != nil {
+			return vpc, err
+		}
+		vpc.ExternalSubnetUUIDs = append(vpc.ExternalSubnetUUIDs, uuid)
+	}
+	return vpc, nil
+}
+
+// fetchSubnetUUID returns the UUID of the subnet named name.
+func fetchSubnetUUID(ctx context.Context, ntxCli *nutanix.Client, name string) (string, error) {
+	subnets, err := ntxCli.ListSubnets(ctx)
+	if err != nil {
+		return "", err
+	}
+	return subnetUUIDByName(subnets, name)
+}
+
+// subnetUUIDByName returns the UUID of the subnet named name in subnets.
+func subnetUUIDByName(subnets []nutanix.SubnetInfo, name string) (string, error) {
+	for _, sn := range subnets {
+		if sn.Name == name {
+			return sn.UUID, nil
+		}
+	}
+	return "", fmt.Errorf("subnet %s not found", name)
+}
+
+// floatingIPExternal manages floating IPs. The external name is the floating
+// IP UUID.
+type floatingIPExternal struct {
+	kube client.Client
+}
+
+func (e *floatingIPExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.FloatingIP) (externalObservation, error) {
+	observed, err := ntxCli.GetFloatingIP(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.Address = observed.Address
+	desired, err := e.floatingIPInfo(ctx, cr)
+	if err != nil {
+		return externalObservation{}, err
+	}
+	upToDate := observed.VPCUUID == desired.VPCUUID && observed.PrivateIP == desired.PrivateIP && observed.VMUUID == ""
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *floatingIPExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.FloatingIP) (string, error) {
+	fip, err := e.floatingIPInfo(ctx, cr)
+	if err != nil {
+		return "", err
+	}
+	if fip.ExternalSubnetUUID, err = fetchSubnetUUID(ctx, ntxCli, cr.Spec.ExternalSubnetName); err != nil {
+		return "", err
+	}
+	uuid, _, err := ntxCli.PutFloatingIP(ctx, fip)
+	return uuid, err
+}
+
+func (e *floatingIPExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.FloatingIP) error {
+	fip, err := e.floatingIPInfo(ctx, cr)
+	if err != nil {
+		return err
+	}
+	fip.UUID, fip.Address = meta.GetExternalName(cr), cr.Status.Address
+	_, _, err = ntxCli.PutFloatingIP(ctx, fip)
+	return err
+}
+
+func (e *floatingIPExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.FloatingIP) error {
+	if err := ntxCli.DeleteFloatingIP(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// floatingIPInfo returns the association described by cr, with its VPC
+// resolved to a UUID. The external subnet is only resolved on creation.
+func (e *floatingIPExternal) floatingIPInfo(ctx context.Context, cr *v1alpha1.FloatingIP) (nutanix.FloatingIPInfo, error) {
+	fip := nutanix.FloatingIPInfo{PrivateIP: cr.Spec.PrivateIP}
+	if cr.Spec.PrivateIP != "" {
+		if cr.Spec.VPCRef == nil {
+			return fip, fmt.Errorf("vpcRef is required with privateIp")
+		}
+		if net.ParseIP(cr.Spec.PrivateIP) == nil {
+			return fip, fmt.Errorf("invalid private IP %q", cr.Spec.PrivateIP)
+		}
+	}
+	if cr.Spec.VPCRef != nil {
+		var vpc v1alpha1.VPC
+		uuid, err := externalNameOf(ctx, e.kube, "VPC", cr.Spec.VPCRef.Name, &vpc)
+		if err != nil {
+			return fip, err
+		}
+		fip.VPCUUID = uuid
+	}
+	return fip, nil
+}
+
+// routingPolicyExternal manages VPC routing policies. The external name is the
+// policy UUID.
+type routingPolicyExternal struct {
+	kube client.Client
+}
+
+func (e *routingPolicyExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RoutingPolicy) (externalObservation, error) {
+	observed, err := ntxCli.GetRoutingPolicy(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	desired, err := e.policyInfo(ctx, cr)
+	if err != nil {
+		return externalObservation{}, err
+	}
+	desired.UUID = observed.UUID
+	return externalObservation{Exists: true, UpToDate: reflect.DeepEqual(*observed, desired)}, nil
+}
+
+func (e *routingPolicyExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RoutingPolicy) (string, error) {
+	policy, err := e.policyInfo(ctx, cr)
+	if err != nil {
+		return "", err
+	}
+	return ntxCli.PutRoutingPolicy(ctx, policy)
+}
+
+func (e *routingPolicyExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RoutingPolicy) error {
+	policy, err := e.policyInfo(ctx, cr)
+	if err != nil {
+		return err
+	}
+	policy.UUID = meta.GetExternalName(cr)
+	_, err = ntxCli.PutRoutingPolicy(ctx, policy)
+	return err
+}
+
+func (e *routingPolicyExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.RoutingPolicy) error {
+	if err := ntxCli.DeleteRoutingPolicy(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// policyInfo validates cr and returns the routing policy it describes, with
+// its VPC resolved to a UUID.
+func (e *routingPolicyExternal) policyInfo(ctx context.Context, cr *v1alpha1.RoutingPolicy) (nutanix.RoutingPolicyInfo, error) {
+	spec := cr.Spec
+	policy := nutanix.RoutingPolicyInfo{
+		Name:          spec.Name,
+		Priority:      spec.Priority,
+		Source:        spec.Source,
+		Destination:   spec.Destination,
+		Protocol:      spec.Protocol,
+		Action:        spec.Action,
+		RerouteIP:     spec.RerouteIP,
+		Bidirectional: spec.Bidirectional,
+	}
+	for field, v := range map[string]string{"source": spec.Source, "destination": spec.Destination} {
+		if v == v1alpha1.RoutingEndpointAny || v == v1alpha1.RoutingEndpointExternal {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(v); err != nil {
+			return policy, fmt.Errorf("%s must be a CIDR, %s or %s", field, v1alpha1.RoutingEndpointAny, v1alpha1.RoutingEndpointExternal)
+		}
+	}
+	if spec.Action == v1alpha1.RoutingActionReroute {
+		if net.ParseIP(spec.RerouteIP) == nil {
+			return policy, fmt.Errorf("a valid rerouteIp is required for REROUTE policies")
+		}
+	} else if spec.RerouteIP != "" {
+		return policy, fmt.Errorf("rerouteIp is only valid for REROUTE policies")
+	}
+	if len(spec.PortRanges) > 0 {
+		if spec.Protocol != "TCP" && spec.Protocol != "UDP" {
+			return policy, fmt.Errorf("portRanges require protocol TCP or UDP")
+		}
+		policy.PortRanges = normalizeServices([]v1alpha1.NetworkService{{PortRanges: spec.PortRanges}})[0].PortRanges
+	}
+
+	var vpc v1alpha1.VPC
+	uuid, err := externalNameOf(ctx, e.kube, "VPC", spec.VPCRef.Name, &vpc)
+	if err != nil {
+		return policy, err
+	}
+	policy.VPCUUID = uuid
+	return policy, nil
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/nutanix"
+)
+
+// SetupVPNGateway adds a controller that reconciles VPNGateway resources.
+func SetupVPNGateway(mgr manager.Manager, o Options) error {
+	return setupExternal[*v1alpha1.VPNGateway](mgr, o, "VPNGateway",
+		func() *v1alpha1.VPNGateway { return &v1alpha1.VPNGateway{} }, &vpnGatewayExternal{kube: mgr.GetClient()})
+}
+
+// vpnGatewayExternal manages VPC VPN gateways and their connections. The
+// external name is the gateway UUID.
+type vpnGatewayExternal struct {
+	kube client.Client
+}
+
+func (e *vpnGatewayExternal) Observe(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPNGateway) (externalObservation, error) {
+	observed, err := ntxCli.GetVPNGateway(ctx, meta.GetExternalName(cr))
+	if errors.Is(err, nutanix.ErrNotFound) {
+		return externalObservation{}, nil
+	}
+	if err != nil {
+		return externalObservation{}, err
+	}
+	cr.Status.UUID = meta.GetExternalName(cr)
+	if observed == nil {
+		return externalObservation{Exists: true, UpToDate: true}, nil
+	}
+	cr.Status.PublicIP = observed.PublicIP
+	cr.Status.Connections = nil
+	for _, c := range observed.Connections {
+		cr.Status.Connections = append(cr.Status.Connections, v1alpha1.VPNConnectionStatus{Name: c.Name, State: c.State})
+	}
+	if err := validateVPNGateway(cr.Spec); err != nil {
+		return externalObservation{}, err
+	}
+	upToDate := observed.Name == cr.Spec.Name &&
+		observed.LocalASN == cr.Spec.LocalASN &&
+		vpnConnectionsUpToDate(observed.Connections, cr.Spec.Connections)
+	return externalObservation{Exists: true, UpToDate: upToDate}, nil
+}
+
+func (e *vpnGatewayExternal) Create(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPNGateway) (string, error) {
+	gateway, err := e.gatewayInfo(ctx, cr)
+	if err != nil {
+		return "", err
+	}
+	if gateway.ClusterUUID, err = fetchClusterUUID(ctx, ntxCli, cr.Spec.ClusterName); err != nil {
+		return "", err
+	}
+	return ntxCli.PutVPNGateway(ctx, gateway)
+}
+
+func (e *vpnGatewayExternal) Update(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPNGateway) error {
+	gateway, err := e.gatewayInfo(ctx, cr)
+	if err != nil {
+		return err
+	}
+	gateway.UUID = meta.GetExternalName(cr)
+	_, err = ntxCli.PutVPNGateway(ctx, gateway)
+	return err
+}
+
+func (e *vpnGatewayExternal) Delete(ctx context.Context, ntxCli *nutanix.Client, cr *v1alpha1.VPNGateway) error {
+	if err := ntxCli.DeleteVPNGateway(ctx, meta.GetExternalName(cr)); err != nil && !errors.Is(err, nutanix.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// gatewayInfo validates cr and returns the VPN gateway it describes, with its
+// VPC resolved to a UUID and its pre-shared keys read. The cluster is only
+// resolved on creation.
+func (e *vpnGatewayExternal) gatewayInfo(ctx context.Context, cr *v1alpha1.VPNGateway) (nutanix.VPNGatewayInfo, error) {
+	gateway := nutanix.VPNGatewayInfo{Name: cr.Spec.Name, LocalASN: cr.Spec.LocalASN}
+	if err := validateVPNGateway(cr.Spec); err != nil {
+		return gateway, err
+	}
+	var vpc v1alpha1.VPC
+	uuid, err := externalNameOf(ctx, e.kube, "VPC", cr.Spec.VPCRef.Name, &vpc)
+	if err != nil {
+		return gateway, err
+	}
+	gateway.VPCUUID = uuid
+
+	for _, c := range cr.Spec.Connections {
+		ref := c.PreSharedKeySecretRef
+		var s corev1.Secret
+		if err := e.kube.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &s); err != nil {
+			return gateway, fmt.Errorf("cannot get pre-shared key of connection %s: %w", c.Name, err)
+		}
+		psk := string(s.Data[ref.Key])
+		if psk == "" {
+			return gateway, fmt.Errorf("pre-shared key of connection %s is empty", c.Name)
+		}
+		gateway.Connections = append(gateway.Connections, nutanix.VPNConnectionInfo{
+			Name:         c.Name,
+			PeerIP:       c.PeerIP,
+			PreSharedKey: psk,
+			RemoteASN:    c.RemoteASN,
+			RemoteCIDRs:  c.RemoteCIDRs,
+		})
+	}
+	return gateway, nil
+}
+
+// validateVPNGateway checks that the connections of spec match its routing:
+// BGP connections need a remote ASN, static ones remote CIDRs.
+func validateVPNGateway(spec v1alpha1.VPNGatewaySpec) error {
+	names := map[string]bool{}
+	for _, c := range spec.Connections {
+		if names[c.Name] {
+			return fmt.Errorf("duplicate connection %s", c.Name)
+		}
+		names[c.Name] = true
+		if net.ParseIP(c.PeerIP) == nil {
+			return fmt.Errorf("invalid peer IP %q of connection %s", c.PeerIP, c.Name)
+		}
+		if spec.LocalASN != 0 {
+			if c.RemoteASN == 0 || len(c.RemoteCIDRs) > 0 {
+				return fmt.Errorf("connection %s of a BGP gateway requires remoteAsn and no remoteCidrs", c.Name)
+			}
+			continue
+		}
+		if c.RemoteASN != 0 || len(c.RemoteCIDRs) == 0 {
+			return fmt.Errorf("connection %s of a static gateway requires remoteCidrs and no remoteAsn; set localAsn to use BGP", c.Name)
+		}
+		for _, cidr := range c.RemoteCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("invalid remote CIDR %q of connection %s: %w", cidr, c.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// vpnConnectionsUpToDate reports whether the observed connections match the
+// desired ones. Pre-shared keys cannot be observed and are not compared.
+func vpnConnectionsUpToDate(observed []nutanix.VPNConnectionInfo, desired []v1alpha1.VPNConnection) bool {
+	if len(observed) != len(desired) {
+		return false
+	}
+	byName := make(map[string]nutanix.VPNConnectionInfo, len(observed))
+	for _, c := range observed {
+		byName[c.Name] = c
+	}
+	for _, d := range desired {
+		o, ok := byName[d.Name]
+		if !ok || o.PeerIP != d.PeerIP || o.RemoteASN != d.RemoteASN ||
+			!reflect.DeepEqual(sortedStrings(o.RemoteCIDRs), sortedStrings(d.RemoteCIDRs)) {
+			return false
+		}
+	}
+	return true
+}
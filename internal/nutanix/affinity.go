@@ -0,0 +1,118 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// HostAffinityPolicyInfo is a VM-host affinity policy.
+type HostAffinityPolicyInfo struct {
+	UUID            string
+	Name            string
+	Description     string
+	VMCategories    map[string]string
+	HostCategories  map[string]string
+	NumVMs          int
+	NumHosts        int
+	NumCompliantVMs int
+}
+
+// VMAntiAffinityPolicyInfo is a VM-VM anti-affinity policy. The VMs in its
+// categories are kept on different hosts of their cluster.
+type VMAntiAffinityPolicyInfo struct {
+	UUID        string
+	Name        string
+	Description string
+	Categories  map[string]string
+}
+
+// GetHostAffinityPolicy is a stub for getting a VM-host affinity policy. It
+// returns ErrNotFound if the policy does not exist; a nil
+// HostAffinityPolicyInfo means it could not be observed.
+func (c *Client) GetHostAffinityPolicy(ctx context.Context, uuid string) (*HostAffinityPolicyInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetHostAffinityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vm_host_affinity_policies/{uuid})
+	return nil, nil
+}
+
+// PutHostAffinityPolicy is a stub for creating or, if policy.UUID is set,
+// updating a VM-host affinity policy. It returns the policy's UUID.
+func (c *Client) PutHostAffinityPolicy(ctx context.Context, policy HostAffinityPolicyInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutHostAffinityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vm_host_affinity_policies or PUT /vm_host_affinity_policies/{uuid})
+	fmt.Printf("[DEBUG] Putting VM-host affinity policy: uuid=%s, name=%s, vmCategories=%v, hostCategories=%v\n", policy.UUID, policy.Name, policy.VMCategories, policy.HostCategories)
+	if policy.UUID != "" {
+		return policy.UUID, nil
+	}
+	return "stub-host-affinity-policy-id", nil
+}
+
+// DeleteHostAffinityPolicy is a stub for deleting a VM-host affinity policy.
+// It returns ErrNotFound if the policy does not exist.
+func (c *Client) DeleteHostAffinityPolicy(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteHostAffinityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /vm_host_affinity_policies/{uuid})
+	fmt.Printf("[DEBUG] Deleting VM-host affinity policy: uuid=%s\n", uuid)
+	return nil
+}
+
+// FindVMAntiAffinityPolicy is a stub for getting the VM-VM anti-affinity
+// policy named name. It returns ErrNotFound if there is no such policy.
+func (c *Client) FindVMAntiAffinityPolicy(ctx context.Context, name string) (*VMAntiAffinityPolicyInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.FindVMAntiAffinityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vmm/v4.0/ahv/policies/vm-anti-affinity-policies with a name filter)
+	return nil, ErrNotFound
+}
+
+// PutVMAntiAffinityPolicy is a stub for creating or, if policy.UUID is set,
+// updating a VM-VM anti-affinity policy. It returns the policy's UUID.
+func (c *Client) PutVMAntiAffinityPolicy(ctx context.Context, policy VMAntiAffinityPolicyInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutVMAntiAffinityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vmm/v4.0/ahv/policies/vm-anti-affinity-policies or PUT .../{uuid})
+	fmt.Printf("[DEBUG] Putting VM-VM anti-affinity policy: uuid=%s, name=%s, categories=%v\n", policy.UUID, policy.Name, policy.Categories)
+	if policy.UUID != "" {
+		return policy.UUID, nil
+	}
+	return "stub-vm-anti-affinity-policy-id", nil
+}
+
+// DeleteVMAntiAffinityPolicy is a stub for deleting a VM-VM anti-affinity
+// policy. It returns ErrNotFound if the policy does not exist.
+func (c *Client) DeleteVMAntiAffinityPolicy(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteVMAntiAffinityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /vmm/v4.0/ahv/policies/vm-anti-affinity-policies/{uuid})
+	fmt.Printf("[DEBUG] Deleting VM-VM anti-affinity policy: uuid=%s\n", uuid)
+	return nil
+}
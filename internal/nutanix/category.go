@@ -0,0 +1,164 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// CategoryExists is a stub for checking whether the category key exists.
+func (c *Client) CategoryExists(ctx context.Context, key string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CategoryExists", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return false, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /categories/{key})
+	return true, nil
+}
+
+// CategoryValueExists is a stub for checking whether value exists under the
+// category key.
+func (c *Client) CategoryValueExists(ctx context.Context, key, value string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CategoryValueExists", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return false, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /categories/{key}/{value})
+	return true, nil
+}
+
+// EnsureCategoryValue is a stub for creating a category value under key if it
+// does not already exist.
+func (c *Client) EnsureCategoryValue(ctx context.Context, key, value string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.EnsureCategoryValue", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /categories/{key}/{value})
+	return nil
+}
+
+// CategoryInfo is a Prism category key.
+type CategoryInfo struct {
+	Name          string
+	Description   string
+	Cardinality   int
+	SystemDefined bool
+}
+
+// CategoryValueInfo is a value of a Prism category key.
+type CategoryValueInfo struct {
+	Category      string
+	Value         string
+	Description   string
+	SystemDefined bool
+}
+
+// GetCategory is a stub for getting a category key. It returns ErrNotFound if
+// the key does not exist; a nil CategoryInfo means it could not be observed.
+func (c *Client) GetCategory(ctx context.Context, name string) (*CategoryInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetCategory", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /categories/{name})
+	return nil, nil
+}
+
+// PutCategory is a stub for creating or updating a category key.
+func (c *Client) PutCategory(ctx context.Context, category CategoryInfo) error {
+	ctx, span := tracing.Start(ctx, "nutanix.PutCategory", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /categories/{name})
+	fmt.Printf("[DEBUG] Putting category: name=%s, description=%s, cardinality=%d\n", category.Name, category.Description, category.Cardinality)
+	return nil
+}
+
+// DeleteCategory is a stub for deleting a category key.
+func (c *Client) DeleteCategory(ctx context.Context, name string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteCategory", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /categories/{name})
+	return nil
+}
+
+// ListCategoryValues is a stub for listing the values of a category key.
+func (c *Client) ListCategoryValues(ctx context.Context, category string) ([]CategoryValueInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ListCategoryValues", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /categories/{name}/list)
+	return nil, nil
+}
+
+// GetCategoryValue is a stub for getting a category value. It returns
+// ErrNotFound if the value does not exist; a nil CategoryValueInfo means it
+// could not be observed.
+func (c *Client) GetCategoryValue(ctx context.Context, category, value string) (*CategoryValueInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetCategoryValue", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /categories/{name}/{value})
+	return nil, nil
+}
+
+// PutCategoryValue is a stub for creating or updating a category value.
+func (c *Client) PutCategoryValue(ctx context.Context, value CategoryValueInfo) error {
+	ctx, span := tracing.Start(ctx, "nutanix.PutCategoryValue", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /categories/{name}/{value})
+	fmt.Printf("[DEBUG] Putting category value: %s=%s, description=%s\n", value.Category, value.Value, value.Description)
+	return nil
+}
+
+// DeleteCategoryValue is a stub for deleting a category value.
+func (c *Client) DeleteCategoryValue(ctx context.Context, category, value string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteCategoryValue", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /categories/{name}/{value})
+	return nil
+}
+
+// CountCategoryValueUsage is a stub for counting the entities (VMs, subnets,
+// policies, ...) a category value is attached to.
+func (c *Client) CountCategoryValueUsage(ctx context.Context, category, value string) (int, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CountCategoryValueUsage", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return 0, err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /category/query with usage_type APPLIED_TO)
+	return 0, nil
+}
@@ -2,10 +2,16 @@ package nutanix
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
 
+	"golang.org/x/time/rate"
+
 	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
 )
 
 // Client is a stub for the Nutanix Prism API client.
@@ -14,21 +20,59 @@ type Client struct {
 	Username string
 	Password string
 	Insecure bool
+
+	// HTTPClient is used for Prism API requests. Its transport records a
+	// client span per request and propagates W3C trace context headers.
+	HTTPClient *http.Client
+
+	// RateLimiter, if set, is waited on before every Prism API call. It is
+	// usually shared by all clients so that it acts as a global limit.
+	RateLimiter *rate.Limiter
 }
 
+// ErrNotFound is returned when a requested Prism object does not exist.
+var ErrNotFound = errors.New("not found in Prism")
+
 // NewClient creates a new Nutanix API client.
 func NewClient(endpoint, username, password string, insecure bool) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402 -- opt-in via credentials
+	}
 	return &Client{
-		Endpoint: endpoint,
-		Username: username,
-		Password: password,
-		Insecure: insecure,
+		Endpoint:   endpoint,
+		Username:   username,
+		Password:   password,
+		Insecure:   insecure,
+		HTTPClient: &http.Client{Transport: tracing.Transport(transport)},
+	}
+}
+
+// wait blocks until the client's rate limiter allows another Prism API call.
+func (c *Client) wait(ctx context.Context) error {
+	if c.RateLimiter == nil {
+		return nil
 	}
+	return c.RateLimiter.Wait(ctx)
+}
+
+// GuestCustomization holds the administrator credentials configured in the
+// guest OS when a VM is created.
+type GuestCustomization struct {
+	Username         string
+	Password         string
+	SSHAuthorizedKey string
 }
 
 // CreateVM creates a VM with additional disks using the Nutanix API.
 // Update CreateVM to accept VirtualMachineSpec and handle additionalDisks
-func (c *Client) CreateVM(ctx context.Context, spec interface{}) (string, error) {
+func (c *Client) CreateVM(ctx context.Context, spec interface{}, guest *GuestCustomization) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateVM", tracing.AttrEndpoint.String(c.Endpoint))
+	defer func() { tracing.End(span, err) }()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
 	// Accepts v1alpha1.VirtualMachineSpec
 	var vmSpec v1alpha1.VirtualMachineSpec
 	switch s := spec.(type) {
@@ -45,23 +89,53 @@ func (c *Client) CreateVM(ctx context.Context, spec interface{}) (string, error)
 
 	// Build disks payload
 	disks := []map[string]interface{}{}
-	// Boot disk (main image)
+	// Boot disk (main image). Without a size it is cloned at the image's
+	// size; with one Prism grows it after cloning.
 	if vmSpec.ImageUUID != "" {
-		disks = append(disks, map[string]interface{}{
+		boot := v1alpha1.BootDiskSpec{}
+		if vmSpec.BootDisk != nil {
+			boot = *vmSpec.BootDisk
+		}
+		entry := map[string]interface{}{
+			"deviceType":  v1alpha1.DeviceTypeDisk,
+			"bus":         DiskBus(boot.Bus, v1alpha1.DeviceTypeDisk),
 			"deviceIndex": 0,
-			"sizeGb":      vmSpec.MemorySizeMiB, // Example, real size should be from image or spec
 			"imageUuid":   vmSpec.ImageUUID,
-		})
+		}
+		if boot.SizeGb > 0 {
+			entry["sizeGb"] = boot.SizeGb
+		}
+		if boot.StorageContainerUUID != "" {
+			entry["storageContainerUuid"] = boot.StorageContainerUUID
+		}
+		if boot.FlashMode {
+			entry["flashMode"] = true
+		}
+		disks = append(disks, entry)
 	}
-	// Additional disks
+	// Additional disks and CD-ROMs
 	for _, disk := range vmSpec.AdditionalDisks {
+		deviceType := disk.DeviceType
+		if deviceType == "" {
+			deviceType = v1alpha1.DeviceTypeDisk
+		}
 		entry := map[string]interface{}{
+			"deviceType":  deviceType,
+			"bus":         DiskBus(disk.Bus, deviceType),
 			"deviceIndex": disk.DeviceIndex,
-			"sizeGb":      disk.SizeGb,
+		}
+		if disk.SizeGb > 0 {
+			entry["sizeGb"] = disk.SizeGb
 		}
 		if disk.ImageUUID != "" {
 			entry["imageUuid"] = disk.ImageUUID
 		}
+		if disk.StorageContainerUUID != "" {
+			entry["storageContainerUuid"] = disk.StorageContainerUUID
+		}
+		if disk.FlashMode {
+			entry["flashMode"] = true
+		}
 		disks = append(disks, entry)
 	}
 
@@ -71,28 +145,295 @@ func (c *Client) CreateVM(ctx context.Context, spec interface{}) (string, error)
 		externalFacts = vmSpec.ExternalFacts
 	}
 
-	// TODO: Replace with actual Nutanix API call to create VM with disks and external facts
-	fmt.Printf("[DEBUG] Creating VM: name=%s, disks=%v, externalFacts=%v\n", vmSpec.Name, disks, externalFacts)
-	return "stub-vm-id", nil
+	// Guest customization (cloud-init / sysprep) payload if credentials are set
+	guestUser := ""
+	if guest != nil {
+		guestUser = guest.Username
+	}
+
+	// Nutanix Guest Tools payload if requested
+	var guestTools map[string]interface{}
+	if gt := vmSpec.GuestTools; gt != nil && gt.Enabled {
+		guestTools = map[string]interface{}{
+			"state":                   "ENABLED",
+			"enabled_capability_list": gt.Capabilities,
+		}
+		if gt.Mount {
+			guestTools["iso_mount_state"] = "MOUNTED"
+		}
+	}
+
+	// Boot configuration and vTPM payload if set
+	var bootConfig map[string]interface{}
+	if vmSpec.Boot != nil || vmSpec.VTPM {
+		bootConfig = bootConfigPayload(vmSpec.Boot, vmSpec.VTPM)
+	}
+
+	span.SetAttributes(tracing.AttrVMName.String(vmSpec.Name))
+
+	// TODO: Replace with actual Nutanix API call to create VM with resources, disks, external facts, guest customization, guest tools and boot configuration
+	fmt.Printf("[DEBUG] Creating VM: name=%s, project=%s, resources=%v, disks=%v, externalFacts=%v, guestUser=%s, guestTools=%v, bootConfig=%v\n", vmSpec.Name, vmSpec.ProjectUUID, resourcesPayload(vmSpec), disks, externalFacts, guestUser, guestTools, bootConfig)
+	taskUUID, vmUUID := "stub-task-id", "stub-vm-id"
+	span.SetAttributes(tracing.AttrTaskUUID.String(taskUUID), tracing.AttrVMUUID.String(vmUUID))
+	return vmUUID, nil
+}
+
+// VMInfo is the observed state of a Nutanix VM.
+type VMInfo struct {
+	UUID          string
+	Name          string
+	ClusterName   string
+	ClusterUUID   string
+	NumVCPUs      int
+	MemorySizeMiB int
+	Disks         []VMDiskInfo
+	NICs          []VMNICInfo
+	Categories    map[string]string
+	PowerState    string // ON or OFF
+	Boot          VMBootInfo
+	CPU           VMCPUInfo
+	Memory        VMMemoryInfo
+}
+
+// VMCPUInfo is the observed CPU topology and features of a Nutanix VM.
+type VMCPUInfo struct {
+	Sockets                int
+	CoresPerSocket         int
+	ThreadsPerCore         int
+	NUMANodes              int
+	Passthrough            bool
+	HardwareVirtualization bool
+}
+
+// VMMemoryInfo is the observed memory features of a Nutanix VM.
+type VMMemoryInfo struct {
+	Overcommit bool
+	HotPlug    bool
+}
+
+// CPUTopology returns the sockets, cores per socket and threads per core
+// of a VM with numVCPUs, defaulting unset values of cpu.
+func CPUTopology(numVCPUs int, cpu *v1alpha1.CPUConfig) (sockets, coresPerSocket, threadsPerCore int) {
+	if cpu == nil {
+		cpu = &v1alpha1.CPUConfig{}
+	}
+	coresPerSocket, threadsPerCore = cpu.CoresPerSocket, cpu.ThreadsPerCore
+	if coresPerSocket == 0 {
+		coresPerSocket = 1
+	}
+	if threadsPerCore == 0 {
+		threadsPerCore = 1
+	}
+	sockets = cpu.Sockets
+	if sockets == 0 {
+		sockets = numVCPUs / coresPerSocket
+	}
+	return sockets, coresPerSocket, threadsPerCore
+}
+
+// resourcesPayload builds the CPU and memory settings of a VM resources
+// payload. Settings without a CPUConfig or MemoryConfig, and zero
+// numVcpus and memorySizeMib, are left to Prism or the VM's clone source.
+func resourcesPayload(spec v1alpha1.VirtualMachineSpec) map[string]interface{} {
+	payload := map[string]interface{}{}
+	if spec.MemorySizeMiB != 0 {
+		payload["memory_size_mib"] = spec.MemorySizeMiB
+	}
+	if spec.CPU == nil {
+		if spec.NumVCPUs != 0 {
+			payload["num_sockets"] = spec.NumVCPUs
+		}
+	} else {
+		sockets, cores, threads := CPUTopology(spec.NumVCPUs, spec.CPU)
+		payload["num_sockets"] = sockets
+		payload["num_vcpus_per_socket"] = cores
+		payload["num_threads_per_core"] = threads
+		payload["vnuma_config"] = map[string]interface{}{"num_vnuma_nodes": spec.CPU.NUMANodes}
+		payload["enable_cpu_passthrough"] = spec.CPU.Passthrough
+		payload["hardware_virtualization_enabled"] = spec.CPU.HardwareVirtualization
+	}
+	if spec.Memory != nil {
+		payload["memory_overcommit_enabled"] = spec.Memory.Overcommit
+		payload["memory_hot_plug_enabled"] = spec.Memory.HotPlug
+	}
+	return payload
+}
+
+// VMBootInfo is the observed boot configuration of a Nutanix VM.
+type VMBootInfo struct {
+	Type        string // LEGACY or UEFI
+	SecureBoot  bool
+	MachineType string // PC or Q35
+	DeviceOrder []string
+	VTPM        bool
+}
+
+// bootConfigPayload builds the boot_config, machine_type and vtpm_config
+// of a VM resources payload.
+func bootConfigPayload(boot *v1alpha1.BootConfig, vtpm bool) map[string]interface{} {
+	if boot == nil {
+		boot = &v1alpha1.BootConfig{}
+	}
+	bootType := boot.Type
+	if bootType == "" {
+		bootType = v1alpha1.BootTypeLegacy
+	}
+	if boot.SecureBoot {
+		bootType = "SECURE_BOOT"
+	}
+	payload := map[string]interface{}{
+		"boot_config": map[string]interface{}{"boot_type": bootType},
+		"vtpm_config": map[string]interface{}{"vtpm_enabled": vtpm},
+	}
+	if boot.MachineType != "" {
+		payload["machine_type"] = boot.MachineType
+	}
+	if len(boot.DeviceOrder) > 0 {
+		payload["boot_config"].(map[string]interface{})["boot_device_order_list"] = boot.DeviceOrder
+	}
+	return payload
+}
+
+// VMDiskInfo represents a disk or CD-ROM attached to a Nutanix VM.
+type VMDiskInfo struct {
+	DeviceIndex          int
+	DeviceType           string // DISK or CDROM
+	Bus                  string // SCSI, SATA, PCI or IDE
+	SizeGb               int
+	ImageUUID            string
+	StorageContainerUUID string
+	FlashMode            bool
+}
+
+// DiskBus returns bus, defaulting to IDE for CD-ROMs and SCSI for disks.
+func DiskBus(bus, deviceType string) string {
+	switch {
+	case bus != "":
+		return bus
+	case deviceType == v1alpha1.DeviceTypeCDROM:
+		return v1alpha1.DiskBusIDE
+	default:
+		return v1alpha1.DiskBusSCSI
+	}
+}
+
+// VMNICInfo represents a network interface attached to a Nutanix VM.
+type VMNICInfo struct {
+	SubnetUUID string
+	IPAddress  string
 }
 
-// GetVM is a stub for getting a VM.
-func (c *Client) GetVM(ctx context.Context, vmID string) (interface{}, error) {
+// GetVM is a stub for getting a VM. A nil VMInfo means the VM's state could
+// not be observed.
+func (c *Client) GetVM(ctx context.Context, vmID string) (*VMInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetVM", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// TODO: Implement actual Nutanix API call
 	return nil, nil
 }
 
+// FindVMsByName is a stub for listing the VMs whose name exactly matches name.
+func (c *Client) FindVMsByName(ctx context.Context, name string) ([]VMInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.FindVMsByName", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMName.String(name))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vms/list with a vm_name filter)
+	return nil, nil
+}
+
+// UpdateVM is a stub for updating the resources, disks, NICs and categories
+// of an existing VM to match spec. The CPU and memory settings of a nil
+// spec.CPU or spec.Memory are left unchanged, as are the disks of VMs
+// restored from a recovery point or cloned from a source, which spec does
+// not list. Boot settings are changed through UpdateBootConfig.
+func (c *Client) UpdateVM(ctx context.Context, vmID string, spec v1alpha1.VirtualMachineSpec) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateVM", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /vms/{uuid}), keeping
+	// the observed disk_list when disks are not managed
+	managesDisks := spec.RestoreFrom == nil && spec.Source == nil
+	fmt.Printf("[DEBUG] Updating VM: uuid=%s, resources=%v, managesDisks=%t\n", vmID, resourcesPayload(spec), managesDisks)
+	return nil
+}
+
+// UpdateVMCategories is a stub for setting the categories in set and removing
+// the category keys in remove on a VM, leaving its other categories and
+// settings as they are.
+func (c *Client) UpdateVMCategories(ctx context.Context, vmID string, set map[string]string, remove []string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateVMCategories", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET and PUT /vms/{uuid},
+	// changing only metadata.categories)
+	fmt.Printf("[DEBUG] Updating VM categories: uuid=%s, set=%v, remove=%v\n", vmID, set, remove)
+	return nil
+}
+
+// UpdateBootConfig is a stub for changing the firmware, machine type, boot
+// order and vTPM of a VM. Prism only accepts these changes while the VM is
+// powered off.
+func (c *Client) UpdateBootConfig(ctx context.Context, vmID string, boot *v1alpha1.BootConfig, vtpm bool) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateBootConfig", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /vms/{uuid})
+	fmt.Printf("[DEBUG] Updating VM boot configuration: uuid=%s, bootConfig=%v\n", vmID, bootConfigPayload(boot, vtpm))
+	return nil
+}
+
+// SetPowerState is a stub for powering a VM ON or OFF.
+func (c *Client) SetPowerState(ctx context.Context, vmID, state string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.SetPowerState", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call
+	fmt.Printf("[DEBUG] Setting VM power state: uuid=%s, state=%s\n", vmID, state)
+	return nil
+}
+
 // DeleteVM is a stub for deleting a VM.
 func (c *Client) DeleteVM(ctx context.Context, vmID string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteVM", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
 	// TODO: Implement actual Nutanix API call
 	return nil
 }
 
 // ListClusters fetches the list of clusters from Nutanix Prism Central.
-func (c *Client) ListClusters() ([]struct {
+func (c *Client) ListClusters(ctx context.Context) ([]struct {
 	Name string
 	UUID string
 }, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ListClusters", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// TODO: Implement actual Nutanix API call to fetch clusters
 	return []struct {
 		Name string
@@ -102,38 +443,3 @@ func (c *Client) ListClusters() ([]struct {
 		{Name: "aza-ntnx-02", UUID: "11111111-1111-1111-1111-111111111111"},
 	}, nil
 }
-
-// ImageInfo represents a Nutanix image.
-type ImageInfo struct {
-	Name        string
-	UUID        string
-	CreatedTime int64 // Unix timestamp
-}
-
-// ListImages fetches the list of images from Nutanix Prism Central.
-func (c *Client) ListImages(ctx context.Context) ([]ImageInfo, error) {
-	// TODO: Implement actual Nutanix API call to fetch images
-	return []ImageInfo{
-		{Name: "ubuntu-22.04-cloud", UUID: "img-uuid-1", CreatedTime: 1710000000},
-		{Name: "rhel8-latest", UUID: "img-uuid-2", CreatedTime: 1720000000},
-		{Name: "rhel8-2024-06", UUID: "img-uuid-3", CreatedTime: 1730000000},
-		{Name: "win2022-2025-01", UUID: "img-uuid-4", CreatedTime: 1740000000},
-	}, nil
-}
-
-// SubnetInfo represents a Nutanix subnet.
-type SubnetInfo struct {
-	Name        string
-	UUID        string
-	CreatedTime int64 // Unix timestamp
-}
-
-// ListSubnets fetches the list of subnets from Nutanix Prism Central.
-func (c *Client) ListSubnets(ctx context.Context) ([]SubnetInfo, error) {
-	// TODO: Implement actual Nutanix API call to fetch subnets
-	return []SubnetInfo{
-		{Name: "prod-subnet", UUID: "subnet-uuid-1", CreatedTime: 1710000000},
-		{Name: "dev-subnet", UUID: "subnet-uuid-2", CreatedTime: 1720000000},
-		{Name: "rhel8-subnet", UUID: "subnet-uuid-3", CreatedTime: 1730000000},
-	}, nil
-}
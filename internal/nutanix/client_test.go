@@ -0,0 +1,48 @@
+package nutanix
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+)
+
+func TestCPUTopology(t *testing.T) {
+	type want struct {
+		sockets, cores, threads int
+	}
+	cases := map[string]struct {
+		reason   string
+		numVCPUs int
+		cpu      *v1alpha1.CPUConfig
+		want     want
+	}{
+		"NilConfig": {
+			reason:   "Without a CPU config every vCPU is its own single-core socket.",
+			numVCPUs: 4,
+			want:     want{sockets: 4, cores: 1, threads: 1},
+		},
+		"DerivedSockets": {
+			reason:   "Sockets default to numVcpus divided by the cores per socket.",
+			numVCPUs: 8,
+			cpu:      &v1alpha1.CPUConfig{CoresPerSocket: 4, ThreadsPerCore: 2},
+			want:     want{sockets: 2, cores: 4, threads: 2},
+		},
+		"ExplicitSockets": {
+			reason:   "Explicit sockets are returned as set, even if they do not match numVcpus.",
+			numVCPUs: 8,
+			cpu:      &v1alpha1.CPUConfig{Sockets: 3, CoresPerSocket: 2},
+			want:     want{sockets: 3, cores: 2, threads: 1},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got want
+			got.sockets, got.cores, got.threads = CPUTopology(tc.numVCPUs, tc.cpu)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nCPUTopology(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// AddressGroupInfo is a Flow address group.
+type AddressGroupInfo struct {
+	UUID        string
+	Name        string
+	Description string
+	CIDRs       []string
+}
+
+// ServiceGroupInfo is a Flow service group.
+type ServiceGroupInfo struct {
+	UUID        string
+	Name        string
+	Description string
+	Services    []v1alpha1.NetworkService
+}
+
+// SecurityRuleInfo is a Flow allow list entry with its address and service
+// groups resolved to UUIDs.
+type SecurityRuleInfo struct {
+	Description       string
+	Categories        map[string]string
+	AddressGroupUUID  string
+	CIDR              string
+	ServiceGroupUUIDs []string
+	Services          []v1alpha1.NetworkService
+}
+
+// SecurityPolicyInfo is a Flow security policy.
+type SecurityPolicyInfo struct {
+	UUID             string
+	Name             string
+	Description      string
+	Type             string
+	Mode             string
+	AppliedTo        map[string]string
+	Inbound          []SecurityRuleInfo
+	AllowAllInbound  bool
+	Outbound         []SecurityRuleInfo
+	AllowAllOutbound bool
+	Isolation        *v1alpha1.IsolationGroups
+}
+
+// GetAddressGroup is a stub for getting an address group. It returns
+// ErrNotFound if the group does not exist; a nil AddressGroupInfo means it
+// could not be observed.
+func (c *Client) GetAddressGroup(ctx context.Context, uuid string) (*AddressGroupInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetAddressGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /address_groups/{uuid})
+	return nil, nil
+}
+
+// PutAddressGroup is a stub for creating or, if group.UUID is set, updating
+// an address group. It returns the group's UUID.
+func (c *Client) PutAddressGroup(ctx context.Context, group AddressGroupInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutAddressGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /address_groups or PUT /address_groups/{uuid})
+	fmt.Printf("[DEBUG] Putting address group: uuid=%s, name=%s, cidrs=%v\n", group.UUID, group.Name, group.CIDRs)
+	if group.UUID != "" {
+		return group.UUID, nil
+	}
+	return "stub-address-group-id", nil
+}
+
+// DeleteAddressGroup is a stub for deleting an address group.
+func (c *Client) DeleteAddressGroup(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteAddressGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /address_groups/{uuid})
+	return nil
+}
+
+// GetServiceGroup is a stub for getting a service group. It returns
+// ErrNotFound if the group does not exist; a nil ServiceGroupInfo means it
+// could not be observed.
+func (c *Client) GetServiceGroup(ctx context.Context, uuid string) (*ServiceGroupInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetServiceGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /service_groups/{uuid})
+	return nil, nil
+}
+
+// PutServiceGroup is a stub for creating or, if group.UUID is set, updating
+// a service group. It returns the group's UUID.
+func (c *Client) PutServiceGroup(ctx context.Context, group ServiceGroupInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutServiceGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /service_groups or PUT /service_groups/{uuid})
+	fmt.Printf("[DEBUG] Putting service group: uuid=%s, name=%s, services=%+v\n", group.UUID, group.Name, group.Services)
+	if group.UUID != "" {
+		return group.UUID, nil
+	}
+	return "stub-service-group-id", nil
+}
+
+// DeleteServiceGroup is a stub for deleting a service group.
+func (c *Client) DeleteServiceGroup(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteServiceGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /service_groups/{uuid})
+	return nil
+}
+
+// GetSecurityPolicy is a stub for getting a security policy. It returns
+// ErrNotFound if the policy does not exist; a nil SecurityPolicyInfo means it
+// could not be observed.
+func (c *Client) GetSecurityPolicy(ctx context.Context, uuid string) (*SecurityPolicyInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetSecurityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /network_security_rules/{uuid})
+	return nil, nil
+}
+
+// PutSecurityPolicy is a stub for creating or, if policy.UUID is set,
+// updating a security policy. It returns the policy's UUID.
+func (c *Client) PutSecurityPolicy(ctx context.Context, policy SecurityPolicyInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutSecurityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /network_security_rules or PUT /network_security_rules/{uuid})
+	fmt.Printf("[DEBUG] Putting security policy: uuid=%s, name=%s, type=%s, mode=%s, appliedTo=%v, inbound=%d, outbound=%d\n",
+		policy.UUID, policy.Name, policy.Type, policy.Mode, policy.AppliedTo, len(policy.Inbound), len(policy.Outbound))
+	if policy.UUID != "" {
+		return policy.UUID, nil
+	}
+	return "stub-security-policy-id", nil
+}
+
+// DeleteSecurityPolicy is a stub for deleting a security policy.
+func (c *Client) DeleteSecurityPolicy(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteSecurityPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /network_security_rules/{uuid})
+	return nil
+}
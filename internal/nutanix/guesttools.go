@@ -0,0 +1,76 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// GuestToolsInfo is the observed Nutanix Guest Tools state of a VM.
+type GuestToolsInfo struct {
+	// Enabled is true when NGT is enabled for the VM in Prism.
+	Enabled bool
+	// ISOMounted is true while the NGT installer ISO is mounted.
+	ISOMounted bool
+	// Installed is true once NGT is installed in the guest.
+	Installed bool
+	// Version of NGT installed in the guest.
+	Version string
+	// Reachable is true while the guest agent communicates with the CVM.
+	Reachable bool
+	// Capabilities enabled for the VM.
+	Capabilities []string
+}
+
+// GetGuestTools is a stub for reading a VM's NGT state. A nil GuestToolsInfo
+// means the state could not be observed.
+func (c *Client) GetGuestTools(ctx context.Context, vmID string) (*GuestToolsInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetGuestTools", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vms/{uuid}, status.resources.guest_tools.nutanix_guest_tools)
+	return nil, nil
+}
+
+// EnableGuestTools is a stub for enabling NGT on a VM with capabilities.
+func (c *Client) EnableGuestTools(ctx context.Context, vmID string, capabilities []string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.EnableGuestTools", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /vms/{uuid} with guest_tools.nutanix_guest_tools.state ENABLED)
+	fmt.Printf("[DEBUG] Enabling NGT: uuid=%s, capabilities=%v\n", vmID, capabilities)
+	return nil
+}
+
+// MountGuestTools is a stub for mounting the NGT installer ISO on a VM.
+func (c *Client) MountGuestTools(ctx context.Context, vmID string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.MountGuestTools", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /vms/{uuid} with guest_tools.nutanix_guest_tools.iso_mount_state MOUNTED)
+	fmt.Printf("[DEBUG] Mounting NGT ISO: uuid=%s\n", vmID)
+	return nil
+}
+
+// DisableGuestTools is a stub for disabling NGT on a VM.
+func (c *Client) DisableGuestTools(ctx context.Context, vmID string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DisableGuestTools", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /vms/{uuid} with guest_tools.nutanix_guest_tools.state DISABLED)
+	fmt.Printf("[DEBUG] Disabling NGT: uuid=%s\n", vmID)
+	return nil
+}
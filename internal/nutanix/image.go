@@ -0,0 +1,110 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// ImageInfo represents a Nutanix image.
+type ImageInfo struct {
+	Name        string
+	UUID        string
+	CreatedTime int64 // Unix timestamp
+	Description string
+	ImageType   string
+	State       string // PENDING, COMPLETE or ERROR
+	SizeBytes   int64
+	Categories  map[string]string
+
+	// Boot requirements of the guest OS on the image.
+	RequiresUEFI       bool
+	RequiresSecureBoot bool
+	RequiresVTPM       bool
+}
+
+// ListImages fetches the list of images from Nutanix Prism Central.
+func (c *Client) ListImages(ctx context.Context) ([]ImageInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ListImages", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call to fetch images
+	return []ImageInfo{
+		{Name: "ubuntu-22.04-cloud", UUID: "img-uuid-1", CreatedTime: 1710000000},
+		{Name: "rhel8-latest", UUID: "img-uuid-2", CreatedTime: 1720000000},
+		{Name: "rhel8-2024-06", UUID: "img-uuid-3", CreatedTime: 1730000000},
+		{Name: "win2022-2025-01", UUID: "img-uuid-4", CreatedTime: 1740000000, RequiresUEFI: true, RequiresSecureBoot: true, RequiresVTPM: true},
+	}, nil
+}
+
+// GetImage is a stub for getting an image. It returns ErrNotFound if the
+// image does not exist; a nil ImageInfo means it could not be observed.
+func (c *Client) GetImage(ctx context.Context, uuid string) (*ImageInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetImage", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /images/{uuid})
+	return nil, nil
+}
+
+// CreateImage is a stub for creating an image from spec. sourceImageUUID
+// replaces spec.Source.ImageName when copying an image by name, and
+// clusterUUIDs are the resolved spec.ClusterNames.
+func (c *Client) CreateImage(ctx context.Context, spec v1alpha1.ImageSpec, sourceImageUUID string, clusterUUIDs []string) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateImage", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	source := map[string]interface{}{}
+	switch {
+	case spec.Source.URL != "":
+		source["source_uri"] = spec.Source.URL
+	case spec.Source.VMDisk != nil:
+		source["data_source_reference"] = map[string]interface{}{"kind": "vm_disk", "vm_uuid": spec.Source.VMDisk.VMUUID, "device_index": spec.Source.VMDisk.DeviceIndex}
+	case sourceImageUUID != "":
+		source["data_source_reference"] = map[string]interface{}{"kind": "image", "uuid": sourceImageUUID}
+	}
+	if spec.Checksum != nil {
+		source["checksum"] = map[string]interface{}{"checksum_algorithm": spec.Checksum.Algorithm, "checksum_value": spec.Checksum.Value}
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /images) and wait for the task
+	fmt.Printf("[DEBUG] Creating image: name=%s, type=%s, source=%v, clusters=%v\n", spec.Name, spec.ImageType, source, clusterUUIDs)
+	return "stub-image-id", nil
+}
+
+// UpdateImage is a stub for updating the name, description and categories of
+// an image.
+func (c *Client) UpdateImage(ctx context.Context, uuid string, spec v1alpha1.ImageSpec) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateImage", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /images/{uuid})
+	fmt.Printf("[DEBUG] Updating image: uuid=%s, name=%s\n", uuid, spec.Name)
+	return nil
+}
+
+// DeleteImage is a stub for deleting an image.
+func (c *Client) DeleteImage(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteImage", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /images/{uuid})
+	return nil
+}
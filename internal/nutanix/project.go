@@ -0,0 +1,94 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// ProjectRefs are the Prism UUIDs of the clusters, subnets and images a
+// project's spec refers to by name.
+type ProjectRefs struct {
+	ClusterUUIDs      []string
+	SubnetUUIDs       []string
+	DefaultSubnetUUID string
+	ImageUUIDs        []string
+}
+
+// ProjectInfo is a Prism project.
+type ProjectInfo struct {
+	Name         string
+	UUID         string
+	Description  string
+	Quota        *v1alpha1.ProjectQuota
+	Refs         ProjectRefs
+	MemberGroups []v1alpha1.ProjectMemberGroup
+}
+
+// ListProjects is a stub for listing projects.
+func (c *Client) ListProjects(ctx context.Context) ([]ProjectInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ListProjects", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /projects/list)
+	return []ProjectInfo{
+		{Name: "default", UUID: "project-uuid-default"},
+	}, nil
+}
+
+// GetProject is a stub for getting a project. It returns ErrNotFound if the
+// project does not exist; a nil ProjectInfo means it could not be observed.
+func (c *Client) GetProject(ctx context.Context, uuid string) (*ProjectInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetProject", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /projects_internal/{uuid})
+	return nil, nil
+}
+
+// CreateProject is a stub for creating a project from spec with its name
+// references resolved to refs.
+func (c *Client) CreateProject(ctx context.Context, spec v1alpha1.ProjectSpec, refs ProjectRefs) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateProject", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /projects_internal) and wait for the task
+	fmt.Printf("[DEBUG] Creating project: name=%s, quota=%+v, refs=%+v, memberGroups=%v\n", spec.Name, spec.Quota, refs, spec.MemberGroups)
+	return "stub-project-id", nil
+}
+
+// UpdateProject is a stub for updating a project to match spec.
+func (c *Client) UpdateProject(ctx context.Context, uuid string, spec v1alpha1.ProjectSpec, refs ProjectRefs) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateProject", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /projects_internal/{uuid})
+	fmt.Printf("[DEBUG] Updating project: uuid=%s, name=%s, refs=%+v\n", uuid, spec.Name, refs)
+	return nil
+}
+
+// DeleteProject is a stub for deleting a project.
+func (c *Client) DeleteProject(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteProject", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /projects/{uuid})
+	return nil
+}
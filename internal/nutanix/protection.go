@@ -0,0 +1,128 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// AvailabilityZoneInfo is an availability zone known to a Prism Central: the
+// local one or a paired remote Prism Central.
+type AvailabilityZoneInfo struct {
+	Name string
+	URL  string
+	// Endpoint is the address of the availability zone's Prism Central.
+	Endpoint string
+}
+
+// ProtectionRuleInfo replicates recovery points from a source to a target
+// availability zone.
+type ProtectionRuleInfo struct {
+	SourceAZURL       string
+	SourceClusterUUID string
+	TargetAZURL       string
+	TargetClusterUUID string
+	RPOSeconds        int64
+	LocalRetention    int
+	RemoteRetention   int
+}
+
+// ProtectionPolicyInfo is a Prism protection policy.
+type ProtectionPolicyInfo struct {
+	UUID         string
+	Name         string
+	Description  string
+	Categories   map[string]string
+	Rules        []ProtectionRuleInfo
+	ProtectedVMs int
+}
+
+// VMProtectionInfo is the replication state of a VM.
+type VMProtectionInfo struct {
+	PolicyName          string
+	State               string
+	ReplicationType     string
+	LastReplicationTime *time.Time
+}
+
+// ListAvailabilityZones is a stub for listing the availability zones known to
+// the Prism Central.
+func (c *Client) ListAvailabilityZones(ctx context.Context) ([]AvailabilityZoneInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ListAvailabilityZones", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /groups with entity_type availability_zone_physical)
+	return []AvailabilityZoneInfo{
+		{Name: "Local AZ", URL: "local-az-url", Endpoint: c.Endpoint},
+	}, nil
+}
+
+// GetProtectionPolicy is a stub for getting a protection policy. It returns
+// ErrNotFound if the policy does not exist; a nil ProtectionPolicyInfo means
+// it could not be observed.
+func (c *Client) GetProtectionPolicy(ctx context.Context, uuid string) (*ProtectionPolicyInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetProtectionPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /protection_rules/{uuid})
+	return nil, nil
+}
+
+// CreateProtectionPolicy is a stub for creating a protection policy.
+func (c *Client) CreateProtectionPolicy(ctx context.Context, policy ProtectionPolicyInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateProtectionPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /protection_rules) and wait for the task
+	fmt.Printf("[DEBUG] Creating protection policy: name=%s, categories=%v, rules=%+v\n", policy.Name, policy.Categories, policy.Rules)
+	return "stub-protection-policy-id", nil
+}
+
+// UpdateProtectionPolicy is a stub for updating a protection policy.
+func (c *Client) UpdateProtectionPolicy(ctx context.Context, uuid string, policy ProtectionPolicyInfo) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateProtectionPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /protection_rules/{uuid})
+	fmt.Printf("[DEBUG] Updating protection policy: uuid=%s, name=%s, rules=%+v\n", uuid, policy.Name, policy.Rules)
+	return nil
+}
+
+// DeleteProtectionPolicy is a stub for deleting a protection policy.
+func (c *Client) DeleteProtectionPolicy(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteProtectionPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /protection_rules/{uuid})
+	return nil
+}
+
+// GetVMProtection is a stub for getting the replication state of a VM. A nil
+// VMProtectionInfo means it could not be observed.
+func (c *Client) GetVMProtection(ctx context.Context, vmID string) (*VMProtectionInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetVMProtection", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vms/{uuid} protection status)
+	return nil, nil
+}
@@ -0,0 +1,110 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// RecoveryPlanInfo is a Prism recovery plan.
+type RecoveryPlanInfo struct {
+	UUID            string
+	Name            string
+	Description     string
+	PrimaryAZURL    string
+	RecoveryAZURL   string
+	Stages          []v1alpha1.RecoveryPlanStage
+	NetworkMappings []v1alpha1.RecoveryPlanNetworkMapping
+}
+
+// RecoveryPlanJobInfo is a job running a recovery plan.
+type RecoveryPlanJobInfo struct {
+	UUID      string
+	State     string
+	Message   string
+	StartTime *time.Time
+	EndTime   *time.Time
+}
+
+// GetRecoveryPlan is a stub for getting a recovery plan. It returns
+// ErrNotFound if the plan does not exist; a nil RecoveryPlanInfo means it
+// could not be observed.
+func (c *Client) GetRecoveryPlan(ctx context.Context, uuid string) (*RecoveryPlanInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetRecoveryPlan", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /recovery_plans/{uuid})
+	return nil, nil
+}
+
+// CreateRecoveryPlan is a stub for creating a recovery plan.
+func (c *Client) CreateRecoveryPlan(ctx context.Context, plan RecoveryPlanInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateRecoveryPlan", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /recovery_plans) and wait for the task
+	fmt.Printf("[DEBUG] Creating recovery plan: name=%s, primary=%s, recovery=%s, stages=%d, networkMappings=%+v\n",
+		plan.Name, plan.PrimaryAZURL, plan.RecoveryAZURL, len(plan.Stages), plan.NetworkMappings)
+	return "stub-recovery-plan-id", nil
+}
+
+// UpdateRecoveryPlan is a stub for updating a recovery plan.
+func (c *Client) UpdateRecoveryPlan(ctx context.Context, uuid string, plan RecoveryPlanInfo) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateRecoveryPlan", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /recovery_plans/{uuid})
+	fmt.Printf("[DEBUG] Updating recovery plan: uuid=%s, name=%s, stages=%d\n", uuid, plan.Name, len(plan.Stages))
+	return nil
+}
+
+// DeleteRecoveryPlan is a stub for deleting a recovery plan.
+func (c *Client) DeleteRecoveryPlan(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteRecoveryPlan", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /recovery_plans/{uuid})
+	return nil
+}
+
+// RunRecoveryPlan is a stub for starting a recovery plan job with action,
+// e.g. TEST_FAILOVER. It returns the job UUID.
+func (c *Client) RunRecoveryPlan(ctx context.Context, uuid, action string) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.RunRecoveryPlan", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /recovery_plan_jobs)
+	fmt.Printf("[DEBUG] Running recovery plan: uuid=%s, action=%s\n", uuid, action)
+	return "stub-recovery-plan-job-id", nil
+}
+
+// GetRecoveryPlanJob is a stub for getting a recovery plan job. A nil
+// RecoveryPlanJobInfo means it could not be observed.
+func (c *Client) GetRecoveryPlanJob(ctx context.Context, uuid string) (*RecoveryPlanJobInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetRecoveryPlanJob", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /recovery_plan_jobs/{uuid})
+	return nil, nil
+}
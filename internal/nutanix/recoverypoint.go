@@ -0,0 +1,90 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// RecoveryPointInfo is a Prism VM recovery point.
+type RecoveryPointInfo struct {
+	UUID           string
+	Name           string
+	VMUUID         string
+	Consistency    string
+	State          string
+	CreationTime   time.Time
+	ExpirationTime *time.Time
+}
+
+// GetRecoveryPoint is a stub for getting a VM recovery point. It returns
+// ErrNotFound if the recovery point does not exist or has expired; a nil
+// RecoveryPointInfo means it could not be observed.
+func (c *Client) GetRecoveryPoint(ctx context.Context, uuid string) (*RecoveryPointInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetRecoveryPoint", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vm_recovery_points/{uuid})
+	return nil, nil
+}
+
+// CreateRecoveryPoint is a stub for taking a recovery point of a VM. A nil
+// expiration keeps the recovery point until it is deleted.
+func (c *Client) CreateRecoveryPoint(ctx context.Context, vmID, name, consistency string, expiration *time.Time) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateRecoveryPoint", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vm_recovery_points) and wait for the task
+	fmt.Printf("[DEBUG] Creating recovery point: vm=%s, name=%s, consistency=%s, expiration=%v\n", vmID, name, consistency, expiration)
+	return "stub-recovery-point-id", nil
+}
+
+// UpdateRecoveryPointExpiration is a stub for changing when a recovery point
+// expires.
+func (c *Client) UpdateRecoveryPointExpiration(ctx context.Context, uuid string, expiration *time.Time) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateRecoveryPointExpiration", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /vm_recovery_points/{uuid})
+	fmt.Printf("[DEBUG] Updating recovery point expiration: uuid=%s, expiration=%v\n", uuid, expiration)
+	return nil
+}
+
+// DeleteRecoveryPoint is a stub for deleting a VM recovery point.
+func (c *Client) DeleteRecoveryPoint(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteRecoveryPoint", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /vm_recovery_points/{uuid})
+	return nil
+}
+
+// RestoreVM is a stub for restoring a recovery point into a new VM named and
+// placed according to spec. It returns the new VM's UUID.
+func (c *Client) RestoreVM(ctx context.Context, recoveryPointUUID string, spec v1alpha1.VirtualMachineSpec) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "nutanix.RestoreVM", tracing.AttrEndpoint.String(c.Endpoint))
+	defer func() { tracing.End(span, err) }()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vm_recovery_points/{uuid}/restore) and wait for the task
+	fmt.Printf("[DEBUG] Restoring VM: recoveryPoint=%s, name=%s, cluster=%s, subnet=%s, project=%s, categories=%v\n",
+		recoveryPointUUID, spec.Name, spec.ClusterUUID, spec.SubnetUUID, spec.ProjectUUID, spec.Categories)
+	return "stub-vm-id", nil
+}
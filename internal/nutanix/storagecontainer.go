@@ -0,0 +1,109 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// StorageContainerInfo is a storage container of a cluster.
+type StorageContainerInfo struct {
+	UUID                    string
+	Name                    string
+	ClusterUUID             string
+	ReplicationFactor       int
+	Compression             bool
+	CompressionDelaySeconds int // only meaningful when Compression is set
+	Deduplication           bool
+	ErasureCoding           bool
+	AdvertisedCapacityGb    int
+	ReservedCapacityGb      int
+	UsedBytes               int64
+	CapacityBytes           int64
+}
+
+// ListStorageContainers is a stub for listing the storage containers of the
+// cluster clusterUUID.
+func (c *Client) ListStorageContainers(ctx context.Context, clusterUUID string) ([]StorageContainerInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ListStorageContainers", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /api/storage/v4.0/config/storage-containers?$filter=clusterExtId eq '{clusterUUID}')
+	return []StorageContainerInfo{
+		{Name: "default-container", UUID: "container-uuid-1", ClusterUUID: clusterUUID},
+	}, nil
+}
+
+// ClusterSupportsFlashMode is a stub reporting whether the cluster
+// clusterUUID has hybrid storage, which flash mode requires.
+func (c *Client) ClusterSupportsFlashMode(ctx context.Context, clusterUUID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ClusterSupportsFlashMode", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return false, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /clusters/{uuid}, checking for an HDD storage tier)
+	return true, nil
+}
+
+// GetStorageContainer is a stub for getting a storage container. It returns
+// ErrNotFound if the container does not exist; a nil StorageContainerInfo
+// means it could not be observed.
+func (c *Client) GetStorageContainer(ctx context.Context, uuid string) (*StorageContainerInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetStorageContainer", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /api/storage/v4.0/config/storage-containers/{uuid} and its stats)
+	return nil, nil
+}
+
+// CreateStorageContainer is a stub for creating a storage container from
+// spec on the cluster clusterUUID.
+func (c *Client) CreateStorageContainer(ctx context.Context, spec v1alpha1.StorageContainerSpec, clusterUUID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateStorageContainer", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /api/storage/v4.0/config/storage-containers) and wait for the task
+	fmt.Printf("[DEBUG] Creating storage container: name=%s, cluster=%s, rf=%d, compression=%+v, dedup=%t, ec=%t, advertised=%d, reserved=%d\n",
+		spec.Name, clusterUUID, spec.ReplicationFactor, spec.Compression, spec.Deduplication, spec.ErasureCoding, spec.AdvertisedCapacityGb, spec.ReservedCapacityGb)
+	return "stub-storage-container-id", nil
+}
+
+// UpdateStorageContainer is a stub for updating a storage container to match
+// spec.
+func (c *Client) UpdateStorageContainer(ctx context.Context, uuid string, spec v1alpha1.StorageContainerSpec) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateStorageContainer", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /api/storage/v4.0/config/storage-containers/{uuid})
+	fmt.Printf("[DEBUG] Updating storage container: uuid=%s, name=%s, rf=%d, compression=%+v, dedup=%t, ec=%t\n",
+		uuid, spec.Name, spec.ReplicationFactor, spec.Compression, spec.Deduplication, spec.ErasureCoding)
+	return nil
+}
+
+// DeleteStorageContainer is a stub for deleting an empty storage container.
+func (c *Client) DeleteStorageContainer(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteStorageContainer", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /api/storage/v4.0/config/storage-containers/{uuid})
+	return nil
+}
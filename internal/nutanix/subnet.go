@@ -0,0 +1,94 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// SubnetInfo represents a Nutanix subnet.
+type SubnetInfo struct {
+	Name        string
+	UUID        string
+	CreatedTime int64 // Unix timestamp
+	Description string
+	SubnetType  string // VLAN or OVERLAY
+	VLANID      int
+	// VPCUUID is the VPC of an OVERLAY subnet.
+	VPCUUID string
+	// IPConfig is nil when Prism IPAM is disabled for the subnet.
+	IPConfig   *v1alpha1.SubnetIPConfig
+	Categories map[string]string
+	FreeIPs    int
+}
+
+// ListSubnets fetches the list of subnets from Nutanix Prism Central.
+func (c *Client) ListSubnets(ctx context.Context) ([]SubnetInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ListSubnets", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call to fetch subnets
+	return []SubnetInfo{
+		{Name: "prod-subnet", UUID: "subnet-uuid-1", CreatedTime: 1710000000},
+		{Name: "dev-subnet", UUID: "subnet-uuid-2", CreatedTime: 1720000000},
+		{Name: "rhel8-subnet", UUID: "subnet-uuid-3", CreatedTime: 1730000000},
+	}, nil
+}
+
+// GetSubnet is a stub for getting a subnet. It returns ErrNotFound if the
+// subnet does not exist; a nil SubnetInfo means it could not be observed.
+func (c *Client) GetSubnet(ctx context.Context, uuid string) (*SubnetInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetSubnet", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /subnets/{uuid})
+	return nil, nil
+}
+
+// CreateSubnet is a stub for creating a subnet from spec. clusterUUID is the
+// resolved spec.ClusterName of a VLAN subnet.
+func (c *Client) CreateSubnet(ctx context.Context, spec v1alpha1.SubnetSpec, clusterUUID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateSubnet", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /subnets) and wait for the task
+	fmt.Printf("[DEBUG] Creating subnet: name=%s, type=%s, vlan=%d, cluster=%s, vpc=%s, ipConfig=%+v\n", spec.Name, spec.SubnetType, spec.VLANID, clusterUUID, spec.VPCUUID, spec.IPConfig)
+	return "stub-subnet-id", nil
+}
+
+// UpdateSubnet is a stub for updating the name, description, IP pools, DHCP
+// options and categories of a subnet.
+func (c *Client) UpdateSubnet(ctx context.Context, uuid string, spec v1alpha1.SubnetSpec) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateSubnet", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /subnets/{uuid})
+	fmt.Printf("[DEBUG] Updating subnet: uuid=%s, name=%s\n", uuid, spec.Name)
+	return nil
+}
+
+// DeleteSubnet is a stub for deleting a subnet.
+func (c *Client) DeleteSubnet(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteSubnet", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /subnets/{uuid})
+	return nil
+}
@@ -0,0 +1,189 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// TemplateInfo is a VM template.
+type TemplateInfo struct {
+	UUID        string
+	Name        string
+	Description string
+	Versions    []TemplateVersionInfo // oldest first
+}
+
+// TemplateVersionInfo is a published version of a VM template.
+type TemplateVersionInfo struct {
+	UUID          string
+	Name          string
+	Description   string
+	Active        bool
+	ClusterUUID   string
+	NumVCPUs      int
+	MemorySizeMiB int
+}
+
+// ActiveVersion returns the template's active version, or nil if it has none.
+func (t *TemplateInfo) ActiveVersion() *TemplateVersionInfo {
+	for i := range t.Versions {
+		if t.Versions[i].Active {
+			return &t.Versions[i]
+		}
+	}
+	return nil
+}
+
+// FindTemplate is a stub for getting the VM template named name. It returns
+// ErrNotFound if there is no such template.
+func (c *Client) FindTemplate(ctx context.Context, name string) (*TemplateInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.FindTemplate", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vmm/v4.0/content/templates with a templateName filter)
+	return &TemplateInfo{
+		UUID: "stub-template-id",
+		Name: name,
+		Versions: []TemplateVersionInfo{
+			{UUID: "stub-template-version-id", Name: "v1", Active: true},
+		},
+	}, nil
+}
+
+// GetTemplate is a stub for getting a VM template. It returns ErrNotFound if
+// the template does not exist; a nil TemplateInfo means it could not be
+// observed.
+func (c *Client) GetTemplate(ctx context.Context, uuid string) (*TemplateInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetTemplate", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vmm/v4.0/content/templates/{uuid} and its versions)
+	return nil, nil
+}
+
+// CreateTemplate is a stub for creating a VM template whose first version,
+// named version, is published from the VM vmUUID. It returns the template's
+// UUID.
+func (c *Client) CreateTemplate(ctx context.Context, name, description, vmUUID, version, versionDescription string) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateTemplate", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmUUID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vmm/v4.0/content/templates) and wait for the task
+	fmt.Printf("[DEBUG] Creating VM template: name=%s, vm=%s, version=%s\n", name, vmUUID, version)
+	return "stub-template-id", nil
+}
+
+// UpdateTemplate is a stub for renaming a VM template or changing its
+// description.
+func (c *Client) UpdateTemplate(ctx context.Context, uuid, name, description string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateTemplate", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /vmm/v4.0/content/templates/{uuid})
+	fmt.Printf("[DEBUG] Updating VM template: uuid=%s, name=%s\n", uuid, name)
+	return nil
+}
+
+// PublishTemplateVersion is a stub for publishing a new version of a VM
+// template, named version, from the VM vmUUID and making it active. It
+// returns the version's UUID.
+func (c *Client) PublishTemplateVersion(ctx context.Context, uuid, vmUUID, version, description string) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PublishTemplateVersion", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmUUID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (PUT /vmm/v4.0/content/templates/{uuid} with a new version source) and wait for the task
+	fmt.Printf("[DEBUG] Publishing VM template version: uuid=%s, vm=%s, version=%s\n", uuid, vmUUID, version)
+	return "stub-template-version-id", nil
+}
+
+// SetActiveTemplateVersion is a stub for making an existing version of a VM
+// template its active version.
+func (c *Client) SetActiveTemplateVersion(ctx context.Context, uuid, versionUUID string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.SetActiveTemplateVersion", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vmm/v4.0/content/templates/{uuid}/$actions/set-active-version)
+	fmt.Printf("[DEBUG] Setting active VM template version: uuid=%s, version=%s\n", uuid, versionUUID)
+	return nil
+}
+
+// DeleteTemplateVersion is a stub for deleting an inactive version of a VM
+// template. It returns ErrNotFound if the version does not exist.
+func (c *Client) DeleteTemplateVersion(ctx context.Context, uuid, versionUUID string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteTemplateVersion", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /vmm/v4.0/content/templates/{uuid}/versions/{versionUuid})
+	fmt.Printf("[DEBUG] Deleting VM template version: uuid=%s, version=%s\n", uuid, versionUUID)
+	return nil
+}
+
+// DeleteTemplate is a stub for deleting a VM template and all its versions.
+// It returns ErrNotFound if the template does not exist.
+func (c *Client) DeleteTemplate(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteTemplate", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /vmm/v4.0/content/templates/{uuid})
+	fmt.Printf("[DEBUG] Deleting VM template: uuid=%s\n", uuid)
+	return nil
+}
+
+// CloneVM is a stub for cloning the VM sourceUUID. The name, sizing, CPU and
+// memory settings, NIC, categories and guest customization of spec override
+// the source's; zero numVcpus and memorySizeMib keep the source's values.
+func (c *Client) CloneVM(ctx context.Context, sourceUUID string, spec v1alpha1.VirtualMachineSpec, guest *GuestCustomization) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CloneVM", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMName.String(spec.Name))
+	defer func() { tracing.End(span, err) }()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vmm/v4.0/ahv/config/vms/{uuid}/$actions/clone) and wait for the task
+	fmt.Printf("[DEBUG] Cloning VM: source=%s, name=%s, overrides=%v, subnet=%s, categories=%v, guestCustomization=%t\n",
+		sourceUUID, spec.Name, resourcesPayload(spec), spec.SubnetUUID, spec.Categories, guest != nil)
+	return "stub-vm-id", nil
+}
+
+// DeployTemplate is a stub for deploying a VM from version versionUUID of the
+// template templateUUID on the VM's cluster. spec overrides the version's
+// VM configuration like in CloneVM.
+func (c *Client) DeployTemplate(ctx context.Context, templateUUID, versionUUID string, spec v1alpha1.VirtualMachineSpec, guest *GuestCustomization) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "nutanix.DeployTemplate", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMName.String(spec.Name))
+	defer func() { tracing.End(span, err) }()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vmm/v4.0/content/templates/{uuid}/$actions/deploy) and wait for the task
+	fmt.Printf("[DEBUG] Deploying VM template: template=%s, version=%s, name=%s, cluster=%s, overrides=%v, subnet=%s, categories=%v, guestCustomization=%t\n",
+		templateUUID, versionUUID, spec.Name, spec.ClusterUUID, resourcesPayload(spec), spec.SubnetUUID, spec.Categories, guest != nil)
+	return "stub-vm-id", nil
+}
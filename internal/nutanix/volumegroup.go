@@ -0,0 +1,102 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// VolumeGroupInfo is a Prism volume group.
+type VolumeGroupInfo struct {
+	UUID            string
+	Name            string
+	Description     string
+	Disks           []v1alpha1.VolumeGroupDisk
+	Shared          bool
+	FlashMode       bool
+	ISCSITargetName string
+	Initiators      []string
+	AttachedVMUUIDs []string
+}
+
+// GetVolumeGroup is a stub for getting a volume group. It returns ErrNotFound
+// if the volume group does not exist; a nil VolumeGroupInfo means it could
+// not be observed.
+func (c *Client) GetVolumeGroup(ctx context.Context, uuid string) (*VolumeGroupInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetVolumeGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /volume-groups/{uuid} and its disks and attachments)
+	return nil, nil
+}
+
+// CreateVolumeGroup is a stub for creating a volume group from spec on the
+// cluster clusterUUID. chapSecret is the target CHAP secret, if any.
+func (c *Client) CreateVolumeGroup(ctx context.Context, spec v1alpha1.VolumeGroupSpec, clusterUUID, chapSecret string) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.CreateVolumeGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /volume-groups, then POST /volume-groups/{uuid}/disks) and wait for the tasks
+	fmt.Printf("[DEBUG] Creating volume group: name=%s, cluster=%s, disks=%v, shared=%t, flashMode=%t, iscsi=%+v, chap=%t\n", spec.Name, clusterUUID, spec.Disks, spec.Shared, spec.FlashMode, spec.ISCSI, chapSecret != "")
+	return "stub-volume-group-id", nil
+}
+
+// UpdateVolumeGroup is a stub for updating a volume group to match spec,
+// adding and growing disks as needed.
+func (c *Client) UpdateVolumeGroup(ctx context.Context, uuid string, spec v1alpha1.VolumeGroupSpec, chapSecret string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.UpdateVolumeGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (PATCH /volume-groups/{uuid} and its disks)
+	fmt.Printf("[DEBUG] Updating volume group: uuid=%s, name=%s, disks=%v\n", uuid, spec.Name, spec.Disks)
+	return nil
+}
+
+// DeleteVolumeGroup is a stub for deleting a volume group.
+func (c *Client) DeleteVolumeGroup(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteVolumeGroup", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /volume-groups/{uuid})
+	return nil
+}
+
+// AttachVolumeGroup is a stub for attaching a volume group to a VM.
+func (c *Client) AttachVolumeGroup(ctx context.Context, uuid, vmID string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.AttachVolumeGroup", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /volume-groups/{uuid}/$actions/attach-vm)
+	fmt.Printf("[DEBUG] Attaching volume group: uuid=%s, vm=%s\n", uuid, vmID)
+	return nil
+}
+
+// DetachVolumeGroup is a stub for detaching a volume group from a VM.
+func (c *Client) DetachVolumeGroup(ctx context.Context, uuid, vmID string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DetachVolumeGroup", tracing.AttrEndpoint.String(c.Endpoint), tracing.AttrVMUUID.String(vmID))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /volume-groups/{uuid}/$actions/detach-vm)
+	fmt.Printf("[DEBUG] Detaching volume group: uuid=%s, vm=%s\n", uuid, vmID)
+	return nil
+}
@@ -0,0 +1,258 @@
+package nutanix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mgeorge67701/provider-nutanix/apis/v1alpha1"
+	"github.com/mgeorge67701/provider-nutanix/internal/tracing"
+)
+
+// VPCInfo is a Flow Virtual Networking VPC.
+type VPCInfo struct {
+	UUID                       string
+	Name                       string
+	Description                string
+	ExternalSubnetUUIDs        []string
+	ExternallyRoutablePrefixes []string
+	DNSServers                 []string
+	Categories                 map[string]string
+	SNATIPs                    []string
+}
+
+// FloatingIPInfo is a floating IP. It is associated with either a VM NIC or
+// a private IP of a VPC, or not associated at all.
+type FloatingIPInfo struct {
+	UUID               string
+	Address            string
+	ExternalSubnetUUID string
+	VPCUUID            string
+	PrivateIP          string
+	VMUUID             string
+}
+
+// RoutingPolicyInfo is a VPC routing policy.
+type RoutingPolicyInfo struct {
+	UUID          string
+	Name          string
+	VPCUUID       string
+	Priority      int
+	Source        string
+	Destination   string
+	Protocol      string
+	PortRanges    []v1alpha1.PortRange
+	Action        string
+	RerouteIP     string
+	Bidirectional bool
+}
+
+// VPNConnectionInfo is an IPsec tunnel of a VPN gateway.
+type VPNConnectionInfo struct {
+	Name         string
+	PeerIP       string
+	PreSharedKey string
+	RemoteASN    int64
+	RemoteCIDRs  []string
+	// State is only set on observed connections.
+	State string
+}
+
+// VPNGatewayInfo is a VPC VPN gateway.
+type VPNGatewayInfo struct {
+	UUID        string
+	Name        string
+	VPCUUID     string
+	ClusterUUID string
+	LocalASN    int64
+	PublicIP    string
+	Connections []VPNConnectionInfo
+}
+
+// ListVPCs is a stub for listing the VPCs of Prism Central.
+func (c *Client) ListVPCs(ctx context.Context) ([]VPCInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.ListVPCs", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vpcs/list)
+	return []VPCInfo{}, nil
+}
+
+// GetVPC is a stub for getting a VPC. It returns ErrNotFound if the VPC does
+// not exist; a nil VPCInfo means it could not be observed.
+func (c *Client) GetVPC(ctx context.Context, uuid string) (*VPCInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetVPC", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vpcs/{uuid})
+	return nil, nil
+}
+
+// PutVPC is a stub for creating or, if vpc.UUID is set, updating a VPC. It
+// returns the VPC's UUID.
+func (c *Client) PutVPC(ctx context.Context, vpc VPCInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutVPC", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vpcs or PUT /vpcs/{uuid})
+	fmt.Printf("[DEBUG] Putting VPC: uuid=%s, name=%s, externalSubnets=%v, routablePrefixes=%v\n",
+		vpc.UUID, vpc.Name, vpc.ExternalSubnetUUIDs, vpc.ExternallyRoutablePrefixes)
+	if vpc.UUID != "" {
+		return vpc.UUID, nil
+	}
+	return "stub-vpc-id", nil
+}
+
+// DeleteVPC is a stub for deleting a VPC.
+func (c *Client) DeleteVPC(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteVPC", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /vpcs/{uuid})
+	return nil
+}
+
+// GetFloatingIP is a stub for getting a floating IP. It returns ErrNotFound
+// if the floating IP does not exist; a nil FloatingIPInfo means it could not
+// be observed.
+func (c *Client) GetFloatingIP(ctx context.Context, uuid string) (*FloatingIPInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetFloatingIP", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /floating_ips/{uuid})
+	return nil, nil
+}
+
+// PutFloatingIP is a stub for allocating or, if fip.UUID is set,
+// re-associating a floating IP. It returns the floating IP's UUID and
+// address.
+func (c *Client) PutFloatingIP(ctx context.Context, fip FloatingIPInfo) (uuid, address string, err error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutFloatingIP", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /floating_ips or PUT /floating_ips/{uuid})
+	fmt.Printf("[DEBUG] Putting floating IP: uuid=%s, externalSubnet=%s, vpc=%s, privateIp=%s, vm=%s\n",
+		fip.UUID, fip.ExternalSubnetUUID, fip.VPCUUID, fip.PrivateIP, fip.VMUUID)
+	if fip.UUID != "" {
+		return fip.UUID, fip.Address, nil
+	}
+	return "stub-floating-ip-id", "203.0.113.10", nil
+}
+
+// DeleteFloatingIP is a stub for releasing a floating IP.
+func (c *Client) DeleteFloatingIP(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteFloatingIP", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /floating_ips/{uuid})
+	return nil
+}
+
+// GetRoutingPolicy is a stub for getting a routing policy. It returns
+// ErrNotFound if the policy does not exist; a nil RoutingPolicyInfo means it
+// could not be observed.
+func (c *Client) GetRoutingPolicy(ctx context.Context, uuid string) (*RoutingPolicyInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetRoutingPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /routing_policies/{uuid})
+	return nil, nil
+}
+
+// PutRoutingPolicy is a stub for creating or, if policy.UUID is set,
+// updating a routing policy. It returns the policy's UUID.
+func (c *Client) PutRoutingPolicy(ctx context.Context, policy RoutingPolicyInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutRoutingPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /routing_policies or PUT /routing_policies/{uuid})
+	fmt.Printf("[DEBUG] Putting routing policy: uuid=%s, vpc=%s, priority=%d, %s -> %s, action=%s\n",
+		policy.UUID, policy.VPCUUID, policy.Priority, policy.Source, policy.Destination, policy.Action)
+	if policy.UUID != "" {
+		return policy.UUID, nil
+	}
+	return "stub-routing-policy-id", nil
+}
+
+// DeleteRoutingPolicy is a stub for deleting a routing policy.
+func (c *Client) DeleteRoutingPolicy(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteRoutingPolicy", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /routing_policies/{uuid})
+	return nil
+}
+
+// GetVPNGateway is a stub for getting a VPN gateway and its connections. It
+// returns ErrNotFound if the gateway does not exist; a nil VPNGatewayInfo
+// means it could not be observed.
+func (c *Client) GetVPNGateway(ctx context.Context, uuid string) (*VPNGatewayInfo, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.GetVPNGateway", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// TODO: Implement actual Nutanix API call (GET /vpn_gateways/{uuid} and POST /vpn_connections/list)
+	return nil, nil
+}
+
+// PutVPNGateway is a stub for creating or, if gateway.UUID is set, updating
+// a VPN gateway. Connections missing from gateway.Connections are deleted. It
+// returns the gateway's UUID.
+func (c *Client) PutVPNGateway(ctx context.Context, gateway VPNGatewayInfo) (string, error) {
+	ctx, span := tracing.Start(ctx, "nutanix.PutVPNGateway", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return "", err
+	}
+
+	// TODO: Implement actual Nutanix API call (POST /vpn_gateways or PUT /vpn_gateways/{uuid}, then /vpn_connections)
+	fmt.Printf("[DEBUG] Putting VPN gateway: uuid=%s, name=%s, vpc=%s, cluster=%s, localAsn=%d, connections=%d\n",
+		gateway.UUID, gateway.Name, gateway.VPCUUID, gateway.ClusterUUID, gateway.LocalASN, len(gateway.Connections))
+	if gateway.UUID != "" {
+		return gateway.UUID, nil
+	}
+	return "stub-vpn-gateway-id", nil
+}
+
+// DeleteVPNGateway is a stub for deleting a VPN gateway and its connections.
+func (c *Client) DeleteVPNGateway(ctx context.Context, uuid string) error {
+	ctx, span := tracing.Start(ctx, "nutanix.DeleteVPNGateway", tracing.AttrEndpoint.String(c.Endpoint))
+	defer span.End()
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	// TODO: Implement actual Nutanix API call (DELETE /vpn_gateways/{uuid})
+	return nil
+}